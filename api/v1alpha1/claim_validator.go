@@ -0,0 +1,102 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// claimValidator is implemented by the claims whose validation reads PasswordPolicies and
+// namespaces from the cluster
+type claimValidator interface {
+	runtime.Object
+	validateCreate(ctx context.Context, reader client.Reader) error
+	validateUpdate(ctx context.Context, reader client.Reader, old runtime.Object) error
+}
+
+// claimValidatingHandler is the validating webhook of a claim type. It stands in for
+// webhook.Validator, whose methods have no access to the cluster.
+type claimValidatingHandler struct {
+	claim   claimValidator
+	reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// newClaimValidatingHandler returns the validating webhook of the type of claim, looking up
+// policies with reader
+func newClaimValidatingHandler(claim claimValidator, reader client.Reader) *claimValidatingHandler {
+	return &claimValidatingHandler{claim: claim, reader: reader}
+}
+
+var _ admission.DecoderInjector = &claimValidatingHandler{}
+
+// InjectDecoder implements admission.DecoderInjector
+func (h *claimValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle implements admission.Handler. Claims are rejected when the policies that apply to
+// them cannot be looked up.
+func (h *claimValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if h.reader == nil {
+		return admission.Errored(http.StatusInternalServerError, errors.New("password policies cannot be looked up"))
+	}
+
+	claim := h.claim.DeepCopyObject().(claimValidator)
+	var err error
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.decoder.Decode(req, claim); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = claim.validateCreate(ctx, h.reader)
+	case admissionv1.Update:
+		old := h.claim.DeepCopyObject()
+		if err := h.decoder.DecodeRaw(req.Object, claim); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := h.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = claim.validateUpdate(ctx, h.reader, old)
+	default:
+		return admission.Allowed("")
+	}
+	return validationResponse(err)
+}
+
+// validationResponse denies the request with the status of an invalid claim, and fails it
+// with any other error
+func validationResponse(err error) admission.Response {
+	if err == nil {
+		return admission.Allowed("")
+	}
+	if statusErr, ok := err.(*apierrors.StatusError); ok && apierrors.IsInvalid(statusErr) {
+		status := statusErr.Status()
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &status}}
+	}
+	return admission.Errored(http.StatusInternalServerError, err)
+}
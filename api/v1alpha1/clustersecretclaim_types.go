@@ -0,0 +1,67 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSecretClaimSpec defines the desired state of ClusterSecretClaim
+type ClusterSecretClaimSpec struct {
+	// NamespaceSelector selects the namespaces the Secret is written to.
+	// An empty selector selects every namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Template is the Secret written to every selected namespace. Its namespace must be left empty.
+	Template KubernetesClaim `json:"template"`
+}
+
+// ClusterSecretClaimStatus defines the observed state of ClusterSecretClaim
+type ClusterSecretClaimStatus struct {
+	// Namespaces the Secret is currently written to
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Conditions report the outcome of the last reconcile. Ready is False with reason
+	// ValidationFailed while the spec cannot be synced, the claim is retried once it changes.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+
+// ClusterSecretClaim is the Schema for the clustersecretclaims API
+type ClusterSecretClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSecretClaimSpec   `json:"spec,omitempty"`
+	Status ClusterSecretClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSecretClaimList contains a list of ClusterSecretClaim
+type ClusterSecretClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSecretClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSecretClaim{}, &ClusterSecretClaimList{})
+}
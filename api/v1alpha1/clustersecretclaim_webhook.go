@@ -0,0 +1,132 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var clustersecretclaimlog = logf.Log.WithName("clustersecretclaim-resource")
+
+const clusterSecretClaimValidatePath = "/validate-secret-operator-io-v1alpha1-clustersecretclaim"
+
+func (r *ClusterSecretClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// The validating webhook looks up PasswordPolicies and namespaces with the manager's client.
+	mgr.GetWebhookServer().Register(clusterSecretClaimValidatePath,
+		&webhook.Admission{Handler: newClaimValidatingHandler(r, mgr.GetClient())})
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-secret-operator-io-v1alpha1-clustersecretclaim,mutating=true,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=clustersecretclaims,versions=v1alpha1,name=mclustersecretclaim.secret-operator.io
+
+var _ webhook.Defaulter = &ClusterSecretClaim{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *ClusterSecretClaim) Default() {
+	clustersecretclaimlog.Info("default", "name", r.Name)
+
+	defaultProperties(r.Spec.Template.Properties)
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-secret-operator-io-v1alpha1-clustersecretclaim,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=clustersecretclaims,versions=v1alpha1,name=vclustersecretclaim.secret-operator.io
+
+var _ claimValidator = &ClusterSecretClaim{}
+
+func (r *ClusterSecretClaim) validateCreate(ctx context.Context, reader client.Reader) error {
+	clustersecretclaimlog.Info("validate create", "name", r.Name)
+
+	allErrs, err := r.validateClusterSecretClaim(ctx, reader)
+	if err != nil {
+		return err
+	}
+	return invalidError("ClusterSecretClaim", r.Name, allErrs)
+}
+
+func (r *ClusterSecretClaim) validateUpdate(ctx context.Context, reader client.Reader, old runtime.Object) error {
+	clustersecretclaimlog.Info("validate update", "name", r.Name)
+
+	allErrs, err := r.validateClusterSecretClaim(ctx, reader)
+	if err != nil {
+		return err
+	}
+	if oldClaim, ok := old.(*ClusterSecretClaim); ok && oldClaim.Spec.Template.Name != r.Spec.Template.Name {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "name"),
+			r.Spec.Template.Name, "destination name is immutable"))
+	}
+	return invalidError("ClusterSecretClaim", r.Name, allErrs)
+}
+
+func (r *ClusterSecretClaim) validateClusterSecretClaim(ctx context.Context, reader client.Reader) (field.ErrorList, error) {
+	specPath := field.NewPath("spec")
+	templatePath := specPath.Child("template")
+
+	allErrs := validateKubernetesClaim(&r.Spec.Template, templatePath)
+	// Cluster claims have no secret store to read remote properties from.
+	allErrs = append(allErrs, validateRemoteSources(r.Spec.Template.Properties, false, templatePath.Child("properties"))...)
+	if r.Spec.Template.Namespace != "" {
+		allErrs = append(allErrs, field.Forbidden(templatePath.Child("namespace"),
+			"namespaces are chosen by namespaceSelector and must not be set on the template"))
+	}
+	if r.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.Spec.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), r.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs, nil
+	}
+
+	// The claim has to satisfy the policy of every namespace it currently writes to.
+	namespaces, err := r.selectedNamespaces(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	policyErrs, err := validatePasswordPolicies(ctx, reader, r.Spec.Template.Properties, namespaces, templatePath.Child("properties"))
+	if err != nil {
+		return nil, err
+	}
+	return append(allErrs, policyErrs...), nil
+}
+
+func (r *ClusterSecretClaim) selectedNamespaces(ctx context.Context, reader client.Reader) ([]corev1.Namespace, error) {
+	var opts []client.ListOption
+	if r.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(r.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	var namespaces corev1.NamespaceList
+	if err := reader.List(ctx, &namespaces, opts...); err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %w", err)
+	}
+	return namespaces.Items, nil
+}
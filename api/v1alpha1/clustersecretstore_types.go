@@ -0,0 +1,70 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSecretStoreSpec defines the desired state of ClusterSecretStore
+type ClusterSecretStoreSpec struct {
+	SecretStoreSpec `json:",inline"`
+	// NamespaceSelector restricts which namespaces may reference the store.
+	// An empty selector allows every namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// ClusterSecretStoreStatus defines the observed state of ClusterSecretStore
+type ClusterSecretStoreStatus struct {
+	SecretStoreStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+
+// ClusterSecretStore is the Schema for the clustersecretstores API
+type ClusterSecretStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSecretStoreSpec   `json:"spec,omitempty"`
+	Status ClusterSecretStoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSecretStoreList contains a list of ClusterSecretStore
+type ClusterSecretStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSecretStore `json:"items"`
+}
+
+// GetSpec returns the provider configuration of the store
+func (s *ClusterSecretStore) GetSpec() *SecretStoreSpec {
+	return &s.Spec.SecretStoreSpec
+}
+
+// GetStatus returns the observed state of the store
+func (s *ClusterSecretStore) GetStatus() *SecretStoreStatus {
+	return &s.Status.SecretStoreStatus
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSecretStore{}, &ClusterSecretStoreList{})
+}
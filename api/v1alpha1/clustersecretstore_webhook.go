@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var clustersecretstorelog = logf.Log.WithName("clustersecretstore-resource")
+
+func (r *ClusterSecretStore) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-secret-operator-io-v1alpha1-clustersecretstore,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=clustersecretstores,versions=v1alpha1,name=vclustersecretstore.secret-operator.io
+
+var _ webhook.Validator = &ClusterSecretStore{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterSecretStore) ValidateCreate() error {
+	clustersecretstorelog.Info("validate create", "name", r.Name)
+
+	return invalidError(ClusterSecretStoreKind, r.Name, r.validateClusterSecretStore())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterSecretStore) ValidateUpdate(old runtime.Object) error {
+	clustersecretstorelog.Info("validate update", "name", r.Name)
+
+	return invalidError(ClusterSecretStoreKind, r.Name, r.validateClusterSecretStore())
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterSecretStore) ValidateDelete() error {
+	return nil
+}
+
+func (r *ClusterSecretStore) validateClusterSecretStore() field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validateProvider(&r.Spec.Provider, specPath.Child("provider"))
+	if r.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.Spec.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), r.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+	return allErrs
+}
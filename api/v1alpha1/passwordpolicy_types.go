@@ -0,0 +1,117 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PasswordPolicySpec defines the minimum requirements for generated passwords
+type PasswordPolicySpec struct {
+	// NamespaceSelector restricts the policy to claims in matching namespaces.
+	// An empty selector applies the policy to every namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MinLength int `json:"minLength,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MinDigits int `json:"minDigits,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MinSymbols int `json:"minSymbols,omitempty"`
+	// RequireUpper rejects generators that set noUpper.
+	RequireUpper bool `json:"requireUpper,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// PasswordPolicy is the Schema for the passwordpolicies API
+type PasswordPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PasswordPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PasswordPolicyList contains a list of PasswordPolicy
+type PasswordPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PasswordPolicy `json:"items"`
+}
+
+// Violations returns a description of every requirement of the policy the generator does not meet.
+func (p *PasswordPolicy) Violations(g *PasswordGenerator) []string {
+	var violations []string
+	if g.Length < p.Spec.MinLength {
+		violations = append(violations, fmt.Sprintf("length %d is below the minimum of %d", g.Length, p.Spec.MinLength))
+	}
+	if numDigits := intValue(g.NumDigits); numDigits < p.Spec.MinDigits {
+		violations = append(violations, fmt.Sprintf("numDigits %d is below the minimum of %d", numDigits, p.Spec.MinDigits))
+	}
+	if numSymbols := intValue(g.NumSymbols); numSymbols < p.Spec.MinSymbols {
+		violations = append(violations, fmt.Sprintf("numSymbols %d is below the minimum of %d", numSymbols, p.Spec.MinSymbols))
+	}
+	if p.Spec.RequireUpper && g.NoUpper {
+		violations = append(violations, "upper case characters are required")
+	}
+	return violations
+}
+
+// AppliesTo reports whether the policy selects a namespace with the labels.
+func (p *PasswordPolicy) AppliesTo(namespaceLabels map[string]string) (bool, error) {
+	if p.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector on password policy %s: %w", p.Name, err)
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// PropertyViolations returns the violations of the password generators of properties, one per property.
+func (p *PasswordPolicy) PropertyViolations(properties []SecretClaimProperty) []string {
+	var violations []string
+	for _, property := range properties {
+		generator := property.PropertySource.PropertyGenerator
+		if generator == nil || generator.Password == nil {
+			continue
+		}
+		if v := p.Violations(generator.Password); len(v) > 0 {
+			violations = append(violations, fmt.Sprintf("property %s does not satisfy PasswordPolicy %s: %s",
+				property.Name, p.Name, strings.Join(v, ", ")))
+		}
+	}
+	return violations
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func init() {
+	SchemeBuilder.Register(&PasswordPolicy{}, &PasswordPolicyList{})
+}
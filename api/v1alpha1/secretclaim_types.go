@@ -24,18 +24,18 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// PasswordGenerator generates a random password. Length, NumSymbols, NumDigits and
+// AllowedSymbols are filled in by the defaulting webhook when left empty.
 type PasswordGenerator struct {
-	// +kubebuilder:default=12
 	Length int `json:"length,omitempty"`
-	// +kubebuilder:default=2
-	NumSymbols int `json:"numSymbols,omitempty"`
-	// +kubebuilder:default=2
-	NumDigits int `json:"numDigits,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	NumSymbols *int `json:"numSymbols,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	NumDigits *int `json:"numDigits,omitempty"`
 	// +kubebuilder:default=false
 	NoUpper bool `json:"noUpper,omitempty"`
 	// +kubebuilder:default=true
-	AllowRepeat bool `json:"allowRepeat,omitempty"`
-	// +kubebuilder:default="~!#%^_+-=?,."
+	AllowRepeat    bool   `json:"allowRepeat,omitempty"`
 	AllowedSymbols string `json:"allowedSymbols,omitempty"`
 }
 
@@ -43,8 +43,18 @@ type PropertyGenerator struct {
 	Hmac     bool               `json:"hmac,omitempty"`
 	Password *PasswordGenerator `json:"password,omitempty"`
 }
+
+// RemoteProperty reads the value of a property from the claim's secret store
+type RemoteProperty struct {
+	// Key is the name of the secret in the store
+	Key string `json:"key"`
+	// Version defaults to the latest version
+	Version string `json:"version,omitempty"`
+}
+
 type PropertySource struct {
 	PropertyGenerator *PropertyGenerator `json:"generator,omitempty"`
+	Remote            *RemoteProperty    `json:"remote,omitempty"`
 }
 
 type SecretClaimProperty struct {
@@ -52,27 +62,82 @@ type SecretClaimProperty struct {
 	PropertySource PropertySource `json:"source,omitempty"`
 }
 
+// KubernetesDestination is a Secret the claim's properties are written to
+type KubernetesDestination struct {
+	Name        string            `json:"name,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	SecretType  v1.SecretType     `json:"secretType,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 type KubernetesClaim struct {
-	Name        string                `json:"name,omitempty"`
-	Namespace   string                `json:"namespace,omitempty"`
-	SecretType  v1.SecretType         `json:"secretType,omitempty"`
-	Labels      map[string]string     `json:"labels,omitempty"`
-	Annotations map[string]string     `json:"annotations,omitempty"`
-	Properties  []SecretClaimProperty `json:"properties,omitempty"`
+	KubernetesDestination `json:",inline"`
+	Properties            []SecretClaimProperty `json:"properties,omitempty"`
+}
+
+// DeletionPolicy controls what happens to a destination when its claim is deleted
+type DeletionPolicy string
+
+const (
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+)
+
+// StoreDestination writes every property to the claim's secret store as a separate
+// secret, named after the property with an optional prefix
+type StoreDestination struct {
+	NamePrefix string `json:"namePrefix,omitempty"`
+	// DeletionPolicy Retain keeps the store secrets when the claim is deleted
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// SecretClaimDestination is one of the places the claim's properties are written to.
+// Exactly one destination type must be set.
+type SecretClaimDestination struct {
+	Kubernetes *KubernetesDestination `json:"kubernetes,omitempty"`
+	// SecretStore writes to the store referenced by spec.secretStoreRef
+	SecretStore *StoreDestination `json:"secretStore,omitempty"`
 }
 
 // SecretClaimSpec defines the desired state of SecretClaim
 type SecretClaimSpec struct {
+	// SecretStoreRef selects the store used by store-backed destinations and property sources
+	SecretStoreRef *SecretStoreRef `json:"secretStoreRef,omitempty"`
+	// KubernetesClaim writes the properties to a single Secret. Use Properties and Destinations
+	// to write the same values to several destinations.
 	KubernetesClaim *KubernetesClaim `json:"kubernetes,omitempty"`
+	// Properties are resolved once per reconcile and written to every destination
+	Properties   []SecretClaimProperty    `json:"properties,omitempty"`
+	Destinations []SecretClaimDestination `json:"destinations,omitempty"`
+}
+
+// DestinationStatus reports the result of the last sync of a single destination
+type DestinationStatus struct {
+	// Destination identifies the destination, e.g. kubernetes:namespace/name
+	Destination  string       `json:"destination"`
+	Synced       bool         `json:"synced"`
+	Message      string       `json:"message,omitempty"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 // SecretClaimStatus defines the observed state of SecretClaim
 type SecretClaimStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+	// Conditions report the outcome of the last reconcile. Ready is False with reason
+	// ValidationFailed while the spec cannot be synced, the claim is retried once it changes.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ConditionReady is the condition type reporting whether a claim is synced to all its destinations
+const ConditionReady = "Ready"
+
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // SecretClaim is the Schema for the secretclaims API
 type SecretClaim struct {
@@ -92,6 +157,15 @@ type SecretClaimList struct {
 	Items           []SecretClaim `json:"items"`
 }
 
+// ClaimProperties returns the properties of the claim, from either the single kubernetes
+// destination or the claim-wide property list.
+func (c *SecretClaim) ClaimProperties() []SecretClaimProperty {
+	if c.Spec.KubernetesClaim != nil {
+		return c.Spec.KubernetesClaim.Properties
+	}
+	return c.Spec.Properties
+}
+
 func init() {
 	SchemeBuilder.Register(&SecretClaim{}, &SecretClaimList{})
 }
@@ -0,0 +1,413 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var secretclaimlog = logf.Log.WithName("secretclaim-resource")
+
+const secretClaimValidatePath = "/validate-secret-operator-io-v1alpha1-secretclaim"
+
+func (r *SecretClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// The validating webhook looks up PasswordPolicies and namespace labels with the manager's client.
+	mgr.GetWebhookServer().Register(secretClaimValidatePath,
+		&webhook.Admission{Handler: newClaimValidatingHandler(r, mgr.GetClient())})
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+const (
+	DefaultPasswordLength         = 32
+	DefaultPasswordNumSymbols     = 4
+	DefaultPasswordNumDigits      = 4
+	DefaultPasswordAllowedSymbols = "~!#%^_+-=?,."
+)
+
+// SetPasswordGeneratorDefaults fills in the unset fields of a PasswordGenerator.
+func SetPasswordGeneratorDefaults(g *PasswordGenerator) {
+	if g.Length == 0 {
+		g.Length = DefaultPasswordLength
+	}
+	if g.NumSymbols == nil {
+		numSymbols := DefaultPasswordNumSymbols
+		g.NumSymbols = &numSymbols
+	}
+	if g.NumDigits == nil {
+		numDigits := DefaultPasswordNumDigits
+		g.NumDigits = &numDigits
+	}
+	if g.AllowedSymbols == "" {
+		g.AllowedSymbols = DefaultPasswordAllowedSymbols
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-secret-operator-io-v1alpha1-secretclaim,mutating=true,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=secretclaims,versions=v1alpha1,name=msecretclaim.secret-operator.io
+
+var _ webhook.Defaulter = &SecretClaim{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *SecretClaim) Default() {
+	secretclaimlog.Info("default", "name", r.Name)
+
+	if r.Spec.KubernetesClaim != nil {
+		defaultProperties(r.Spec.KubernetesClaim.Properties)
+	}
+	defaultProperties(r.Spec.Properties)
+}
+
+func defaultProperties(properties []SecretClaimProperty) {
+	for _, property := range properties {
+		generator := property.PropertySource.PropertyGenerator
+		if generator != nil && generator.Password != nil {
+			SetPasswordGeneratorDefaults(generator.Password)
+		}
+	}
+}
+
+// +kubebuilder:rbac:groups=secret-operator.io,resources=passwordpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:webhook:verbs=create;update,path=/validate-secret-operator-io-v1alpha1-secretclaim,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=secretclaims,versions=v1alpha1,name=vsecretclaim.secret-operator.io
+
+var _ claimValidator = &SecretClaim{}
+
+func (r *SecretClaim) validateCreate(ctx context.Context, reader client.Reader) error {
+	secretclaimlog.Info("validate create", "name", r.Name)
+
+	allErrs, err := r.validateSecretClaim(ctx, reader)
+	if err != nil {
+		return err
+	}
+	return invalidError("SecretClaim", r.Name, allErrs)
+}
+
+func (r *SecretClaim) validateUpdate(ctx context.Context, reader client.Reader, old runtime.Object) error {
+	secretclaimlog.Info("validate update", "name", r.Name)
+
+	allErrs, err := r.validateSecretClaim(ctx, reader)
+	if err != nil {
+		return err
+	}
+	if oldClaim, ok := old.(*SecretClaim); ok {
+		allErrs = append(allErrs, validateSecretClaimImmutable(&r.Spec, &oldClaim.Spec)...)
+	}
+	return invalidError("SecretClaim", r.Name, allErrs)
+}
+
+func (r *SecretClaim) validateSecretClaim(ctx context.Context, reader client.Reader) (field.ErrorList, error) {
+	allErrs := r.validateSecretClaimSpec()
+	if len(allErrs) > 0 {
+		return allErrs, nil
+	}
+	return r.validatePasswordPolicies(ctx, reader)
+}
+
+func (r *SecretClaim) validateSecretClaimSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if r.Spec.SecretStoreRef != nil && r.Spec.SecretStoreRef.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("secretStoreRef", "name"), "store name must be set"))
+	}
+	if r.Spec.KubernetesClaim != nil {
+		if len(r.Spec.Destinations) > 0 || len(r.Spec.Properties) > 0 {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("kubernetes"),
+				"may not be combined with properties and destinations"))
+		}
+		allErrs = append(allErrs, validateKubernetesClaim(r.Spec.KubernetesClaim, specPath.Child("kubernetes"))...)
+		allErrs = append(allErrs, validateRemoteSources(r.Spec.KubernetesClaim.Properties, r.Spec.SecretStoreRef != nil,
+			specPath.Child("kubernetes", "properties"))...)
+		return allErrs
+	}
+
+	if len(r.Spec.Destinations) == 0 {
+		allErrs = append(allErrs, field.Required(specPath, "a claim must declare a destination, e.g. kubernetes or destinations"))
+		return allErrs
+	}
+	allErrs = append(allErrs, validateProperties(r.Spec.Properties, specPath.Child("properties"))...)
+	allErrs = append(allErrs, validateRemoteSources(r.Spec.Properties, r.Spec.SecretStoreRef != nil, specPath.Child("properties"))...)
+	allErrs = append(allErrs, r.validateDestinations(specPath.Child("destinations"))...)
+	return allErrs
+}
+
+func validateKubernetesClaim(claim *KubernetesClaim, fldPath *field.Path) field.ErrorList {
+	allErrs := validateKubernetesDestination(&claim.KubernetesDestination, fldPath)
+	allErrs = append(allErrs, validateProperties(claim.Properties, fldPath.Child("properties"))...)
+	return allErrs
+}
+
+func validateKubernetesDestination(destination *KubernetesDestination, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if destination.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "destination secret name must be set"))
+	}
+	return allErrs
+}
+
+func (r *SecretClaim) validateDestinations(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, destination := range r.Spec.Destinations {
+		idxPath := fldPath.Index(i)
+		switch {
+		case destination.Kubernetes == nil && destination.SecretStore == nil:
+			allErrs = append(allErrs, field.Required(idxPath, "one destination type, e.g. kubernetes or secretStore, must be set"))
+			continue
+		case destination.Kubernetes != nil && destination.SecretStore != nil:
+			allErrs = append(allErrs, field.Forbidden(idxPath, "only one destination type may be set"))
+			continue
+		}
+
+		if destination.SecretStore != nil {
+			if r.Spec.SecretStoreRef == nil {
+				allErrs = append(allErrs, field.Required(field.NewPath("spec", "secretStoreRef"),
+					"a secretStore destination requires a secret store"))
+			}
+			target := "secretStore:" + destination.SecretStore.NamePrefix
+			if seen[target] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("secretStore", "namePrefix"), destination.SecretStore.NamePrefix))
+			}
+			seen[target] = true
+			continue
+		}
+
+		allErrs = append(allErrs, validateKubernetesDestination(destination.Kubernetes, idxPath.Child("kubernetes"))...)
+
+		namespace := destination.Kubernetes.Namespace
+		if namespace == "" {
+			namespace = r.Namespace
+		}
+		target := namespace + "/" + destination.Kubernetes.Name
+		if seen[target] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("kubernetes"), target))
+		}
+		seen[target] = true
+	}
+	return allErrs
+}
+
+func validateProperties(properties []SecretClaimProperty, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, property := range properties {
+		idxPath := fldPath.Index(i)
+		if property.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "property name must be set"))
+		} else if seen[property.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), property.Name))
+		}
+		seen[property.Name] = true
+		allErrs = append(allErrs, validatePropertySource(property.PropertySource, idxPath.Child("source"))...)
+	}
+	return allErrs
+}
+
+func validatePropertySource(source PropertySource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case source.PropertyGenerator == nil && source.Remote == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "property must declare a source, e.g. generator or remote"))
+		return allErrs
+	case source.PropertyGenerator != nil && source.Remote != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of generator or remote may be set"))
+		return allErrs
+	}
+	if source.Remote != nil {
+		if source.Remote.Key == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("remote", "key"), "remote key must be set"))
+		}
+		return allErrs
+	}
+	allErrs = append(allErrs, validatePropertyGenerator(source.PropertyGenerator, fldPath.Child("generator"))...)
+	return allErrs
+}
+
+// validateRemoteSources rejects remote properties unless the claim references a secret store
+func validateRemoteSources(properties []SecretClaimProperty, hasStore bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, property := range properties {
+		if property.PropertySource.Remote != nil && !hasStore {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("source", "remote"),
+				"remote properties require a secret store"))
+		}
+	}
+	return allErrs
+}
+
+func validatePropertyGenerator(generator *PropertyGenerator, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if generator.Hmac && generator.Password != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of hmac or password may be set"))
+	}
+	if !generator.Hmac && generator.Password == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "one of hmac or password must be set"))
+	}
+	if generator.Password != nil {
+		allErrs = append(allErrs, validatePasswordGenerator(generator.Password, fldPath.Child("password"))...)
+	}
+	return allErrs
+}
+
+func validatePasswordGenerator(password *PasswordGenerator, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	numDigits, numSymbols := intValue(password.NumDigits), intValue(password.NumSymbols)
+	if password.Length <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("length"), password.Length, "must be greater than 0"))
+	}
+	if numDigits < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("numDigits"), numDigits, "must not be negative"))
+	}
+	if numSymbols < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("numSymbols"), numSymbols, "must not be negative"))
+	}
+	if numDigits+numSymbols > password.Length {
+		allErrs = append(allErrs, field.Invalid(fldPath, numDigits+numSymbols,
+			"numDigits + numSymbols must not exceed length"))
+	}
+	if numSymbols > 0 && password.AllowedSymbols == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("allowedSymbols"), "must be set when numSymbols is greater than 0"))
+	}
+	return allErrs
+}
+
+// validateSecretClaimImmutable rejects changes to the fields identifying the secrets a claim
+// writes. Destinations may be added, removed or reordered, but not renamed in place.
+func validateSecretClaimImmutable(spec, oldSpec *SecretClaimSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.KubernetesClaim != nil && oldSpec.KubernetesClaim != nil {
+		kubernetesPath := field.NewPath("spec", "kubernetes")
+		if spec.KubernetesClaim.Name != oldSpec.KubernetesClaim.Name {
+			allErrs = append(allErrs, field.Invalid(kubernetesPath.Child("name"),
+				spec.KubernetesClaim.Name, "destination name is immutable"))
+		}
+		if spec.KubernetesClaim.Namespace != oldSpec.KubernetesClaim.Namespace {
+			allErrs = append(allErrs, field.Invalid(kubernetesPath.Child("namespace"),
+				spec.KubernetesClaim.Namespace, "destination namespace is immutable"))
+		}
+	}
+
+	targets := destinationTargets(spec.Destinations)
+	oldTargets := destinationTargets(oldSpec.Destinations)
+	isTarget := func(targets []string, target string) bool {
+		for _, t := range targets {
+			if t == target {
+				return true
+			}
+		}
+		return false
+	}
+	destinationsPath := field.NewPath("spec", "destinations")
+	for i := range spec.Destinations {
+		if i >= len(oldSpec.Destinations) || targets[i] == oldTargets[i] {
+			continue
+		}
+		// A target replaced by one that was not written before, while the old one is no longer
+		// written, is a rename of the destination.
+		if !isTarget(oldTargets, targets[i]) && !isTarget(targets, oldTargets[i]) {
+			allErrs = append(allErrs, field.Invalid(destinationsPath.Index(i), targets[i],
+				fmt.Sprintf("destination %s may not be renamed, remove it and add a new destination instead", oldTargets[i])))
+		}
+	}
+	return allErrs
+}
+
+// destinationTargets identifies the secrets written by each destination
+func destinationTargets(destinations []SecretClaimDestination) []string {
+	targets := make([]string, len(destinations))
+	for i, destination := range destinations {
+		switch {
+		case destination.Kubernetes != nil:
+			targets[i] = "kubernetes:" + destination.Kubernetes.Namespace + "/" + destination.Kubernetes.Name
+		case destination.SecretStore != nil:
+			targets[i] = "secretStore:" + destination.SecretStore.NamePrefix
+		}
+	}
+	return targets
+}
+
+// validatePasswordPolicies checks every password generator in the claim against the
+// PasswordPolicies that select the claim's namespace.
+func (r *SecretClaim) validatePasswordPolicies(ctx context.Context, reader client.Reader) (field.ErrorList, error) {
+	var namespace corev1.Namespace
+	if err := reader.Get(ctx, types.NamespacedName{Name: r.Namespace}, &namespace); err != nil {
+		return nil, fmt.Errorf("unable to get namespace %s: %w", r.Namespace, err)
+	}
+	propertiesPath := field.NewPath("spec", "properties")
+	if r.Spec.KubernetesClaim != nil {
+		propertiesPath = field.NewPath("spec", "kubernetes", "properties")
+	}
+	return validatePasswordPolicies(ctx, reader, r.ClaimProperties(), []corev1.Namespace{namespace}, propertiesPath)
+}
+
+// validatePasswordPolicies checks every password generator in properties against the
+// PasswordPolicies that select at least one of the given namespaces.
+func validatePasswordPolicies(ctx context.Context, reader client.Reader, properties []SecretClaimProperty,
+	namespaces []corev1.Namespace, fldPath *field.Path) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	var policies PasswordPolicyList
+	if err := reader.List(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("unable to list password policies: %w", err)
+	}
+
+	for _, policy := range policies.Items {
+		applies, err := policyApplies(policy, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+		for i, property := range properties {
+			generator := property.PropertySource.PropertyGenerator
+			if generator == nil || generator.Password == nil {
+				continue
+			}
+			if violations := policy.Violations(generator.Password); len(violations) > 0 {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("source", "generator", "password"),
+					fmt.Sprintf("does not satisfy PasswordPolicy %s: %s", policy.Name, strings.Join(violations, ", "))))
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+func policyApplies(policy PasswordPolicy, namespaces []corev1.Namespace) (bool, error) {
+	if policy.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	for _, namespace := range namespaces {
+		applies, err := policy.AppliesTo(namespace.Labels)
+		if err != nil || applies {
+			return applies, err
+		}
+	}
+	return false, nil
+}
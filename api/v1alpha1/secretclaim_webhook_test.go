@@ -0,0 +1,399 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func intPtr(i int) *int { return &i }
+
+func passwordProperty(name string, password *PasswordGenerator) SecretClaimProperty {
+	return SecretClaimProperty{
+		Name:           name,
+		PropertySource: PropertySource{PropertyGenerator: &PropertyGenerator{Password: password}},
+	}
+}
+
+func kubernetesDestination(namespace, name string) SecretClaimDestination {
+	return SecretClaimDestination{Kubernetes: &KubernetesDestination{Namespace: namespace, Name: name}}
+}
+
+// errorFields returns the field paths of allErrs
+func errorFields(allErrs field.ErrorList) []string {
+	fields := make([]string, 0, len(allErrs))
+	for _, err := range allErrs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func expectErrorFields(t *testing.T, allErrs field.ErrorList, want []string) {
+	t.Helper()
+	got := errorFields(allErrs)
+	if len(got) != len(want) {
+		t.Fatalf("got errors %v, want errors on %v", allErrs, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got errors %v, want errors on %v", allErrs, want)
+		}
+	}
+}
+
+func TestValidateSecretClaimSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		spec SecretClaimSpec
+		want []string
+	}{
+		{
+			name: "valid kubernetes claim",
+			spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+				KubernetesDestination: KubernetesDestination{Name: "db"},
+				Properties:            []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8, AllowedSymbols: "!"})},
+			}},
+		},
+		{
+			name: "no destination",
+			spec: SecretClaimSpec{
+				Properties: []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8})},
+			},
+			want: []string{"spec"},
+		},
+		{
+			name: "destination without a type",
+			spec: SecretClaimSpec{
+				Properties:   []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8})},
+				Destinations: []SecretClaimDestination{{}},
+			},
+			want: []string{"spec.destinations[0]"},
+		},
+		{
+			name: "kubernetes claim combined with destinations",
+			spec: SecretClaimSpec{
+				KubernetesClaim: &KubernetesClaim{KubernetesDestination: KubernetesDestination{Name: "db"}},
+				Destinations:    []SecretClaimDestination{kubernetesDestination("", "db-copy")},
+			},
+			want: []string{"spec.kubernetes"},
+		},
+		{
+			name: "duplicate kubernetes destination",
+			spec: SecretClaimSpec{
+				Properties:   []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8})},
+				Destinations: []SecretClaimDestination{kubernetesDestination("", "db"), kubernetesDestination("default", "db")},
+			},
+			want: []string{"spec.destinations[1].kubernetes"},
+		},
+		{
+			name: "numDigits and numSymbols exceed length",
+			spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+				KubernetesDestination: KubernetesDestination{Name: "db"},
+				Properties: []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{
+					Length: 8, NumDigits: intPtr(5), NumSymbols: intPtr(4), AllowedSymbols: "!",
+				})},
+			}},
+			want: []string{"spec.kubernetes.properties[0].source.generator.password"},
+		},
+		{
+			name: "hmac and password both set",
+			spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+				KubernetesDestination: KubernetesDestination{Name: "db"},
+				Properties: []SecretClaimProperty{{
+					Name: "password",
+					PropertySource: PropertySource{PropertyGenerator: &PropertyGenerator{
+						Hmac: true, Password: &PasswordGenerator{Length: 8},
+					}},
+				}},
+			}},
+			want: []string{"spec.kubernetes.properties[0].source.generator"},
+		},
+		{
+			name: "generator and remote both set",
+			spec: SecretClaimSpec{
+				SecretStoreRef: &SecretStoreRef{Name: "store"},
+				KubernetesClaim: &KubernetesClaim{
+					KubernetesDestination: KubernetesDestination{Name: "db"},
+					Properties: []SecretClaimProperty{{
+						Name: "password",
+						PropertySource: PropertySource{
+							PropertyGenerator: &PropertyGenerator{Hmac: true},
+							Remote:            &RemoteProperty{Key: "db-password"},
+						},
+					}},
+				},
+			},
+			want: []string{"spec.kubernetes.properties[0].source"},
+		},
+		{
+			name: "remote property without a store",
+			spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+				KubernetesDestination: KubernetesDestination{Name: "db"},
+				Properties: []SecretClaimProperty{{
+					Name:           "password",
+					PropertySource: PropertySource{Remote: &RemoteProperty{Key: "db-password"}},
+				}},
+			}},
+			want: []string{"spec.kubernetes.properties[0].source.remote"},
+		},
+		{
+			name: "secretStore destination without a store",
+			spec: SecretClaimSpec{
+				Properties:   []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8})},
+				Destinations: []SecretClaimDestination{{SecretStore: &StoreDestination{}}},
+			},
+			want: []string{"spec.secretStoreRef"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := &SecretClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			expectErrorFields(t, claim.validateSecretClaimSpec(), tt.want)
+		})
+	}
+}
+
+func TestValidateSecretClaimImmutable(t *testing.T) {
+	kubernetesClaim := func(namespace, name string) SecretClaimSpec {
+		return SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+			KubernetesDestination: KubernetesDestination{Namespace: namespace, Name: name},
+		}}
+	}
+	destinations := func(destinations ...SecretClaimDestination) SecretClaimSpec {
+		return SecretClaimSpec{Destinations: destinations}
+	}
+	storeDestination := func(prefix string) SecretClaimDestination {
+		return SecretClaimDestination{SecretStore: &StoreDestination{NamePrefix: prefix}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new SecretClaimSpec
+		want     []string
+	}{
+		{
+			name: "unchanged kubernetes claim",
+			old:  kubernetesClaim("", "db"),
+			new:  kubernetesClaim("", "db"),
+		},
+		{
+			name: "renamed kubernetes claim",
+			old:  kubernetesClaim("", "db"),
+			new:  kubernetesClaim("", "db2"),
+			want: []string{"spec.kubernetes.name"},
+		},
+		{
+			name: "kubernetes claim moved to another namespace",
+			old:  kubernetesClaim("", "db"),
+			new:  kubernetesClaim("other", "db"),
+			want: []string{"spec.kubernetes.namespace"},
+		},
+		{
+			name: "added destination",
+			old:  destinations(kubernetesDestination("", "db")),
+			new:  destinations(kubernetesDestination("", "db"), storeDestination("db-")),
+		},
+		{
+			name: "removed destination",
+			old:  destinations(kubernetesDestination("", "db"), kubernetesDestination("", "db-copy")),
+			new:  destinations(kubernetesDestination("", "db-copy")),
+		},
+		{
+			name: "reordered destinations",
+			old:  destinations(kubernetesDestination("", "db"), storeDestination("db-")),
+			new:  destinations(storeDestination("db-"), kubernetesDestination("", "db")),
+		},
+		{
+			name: "renamed kubernetes destination",
+			old:  destinations(kubernetesDestination("", "db")),
+			new:  destinations(kubernetesDestination("", "db2")),
+			want: []string{"spec.destinations[0]"},
+		},
+		{
+			name: "kubernetes destination moved to another namespace",
+			old:  destinations(storeDestination(""), kubernetesDestination("", "db")),
+			new:  destinations(storeDestination(""), kubernetesDestination("other", "db")),
+			want: []string{"spec.destinations[1]"},
+		},
+		{
+			name: "changed store name prefix",
+			old:  destinations(storeDestination("db-")),
+			new:  destinations(storeDestination("app-")),
+			want: []string{"spec.destinations[0]"},
+		},
+		{
+			name: "kubernetes destination replaced by a store destination",
+			old:  destinations(kubernetesDestination("", "db")),
+			new:  destinations(storeDestination("")),
+			want: []string{"spec.destinations[0]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectErrorFields(t, validateSecretClaimImmutable(&tt.new, &tt.old), tt.want)
+		})
+	}
+}
+
+func TestSecretClaimDefault(t *testing.T) {
+	claim := &SecretClaim{Spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+		KubernetesDestination: KubernetesDestination{Name: "db"},
+		Properties: []SecretClaimProperty{
+			passwordProperty("password", &PasswordGenerator{}),
+			passwordProperty("pin", &PasswordGenerator{Length: 6, NumDigits: intPtr(6), NumSymbols: intPtr(0)}),
+		},
+	}}}
+	claim.Default()
+
+	defaulted := claim.Spec.KubernetesClaim.Properties[0].PropertySource.PropertyGenerator.Password
+	if defaulted.Length != 32 || intValue(defaulted.NumDigits) != 4 || intValue(defaulted.NumSymbols) != 4 ||
+		defaulted.AllowedSymbols != DefaultPasswordAllowedSymbols {
+		t.Errorf("got defaults length %d, numDigits %d, numSymbols %d, allowedSymbols %q",
+			defaulted.Length, intValue(defaulted.NumDigits), intValue(defaulted.NumSymbols), defaulted.AllowedSymbols)
+	}
+	set := claim.Spec.KubernetesClaim.Properties[1].PropertySource.PropertyGenerator.Password
+	if set.Length != 6 || intValue(set.NumDigits) != 6 || intValue(set.NumSymbols) != 0 {
+		t.Errorf("defaults overwrote set fields: length %d, numDigits %d, numSymbols %d",
+			set.Length, intValue(set.NumDigits), intValue(set.NumSymbols))
+	}
+}
+
+func newPolicyReader(t *testing.T, objects ...client.Object) client.Reader {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestSecretClaimPasswordPolicies(t *testing.T) {
+	namespaces := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+	}
+	policy := &PasswordPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "strong"},
+		Spec: PasswordPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			MinLength:         24,
+		},
+	}
+	reader := newPolicyReader(t, append(namespaces, policy)...)
+
+	tests := []struct {
+		name      string
+		namespace string
+		length    int
+		wantErr   string
+	}{
+		{name: "strong enough", namespace: "prod", length: 32},
+		{name: "weaker than the policy", namespace: "prod", length: 8, wantErr: "does not satisfy PasswordPolicy strong"},
+		{name: "namespace not selected by the policy", namespace: "dev", length: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := &SecretClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: tt.namespace},
+				Spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+					KubernetesDestination: KubernetesDestination{Name: "db"},
+					Properties: []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{
+						Length: tt.length, NumDigits: intPtr(2), NumSymbols: intPtr(0),
+					})},
+				}},
+			}
+			err := claim.validateCreate(context.Background(), reader)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("got error %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClaimValidatingHandlerFailsClosed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claim := &SecretClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "SecretClaim"},
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "missing"},
+		Spec: SecretClaimSpec{KubernetesClaim: &KubernetesClaim{
+			KubernetesDestination: KubernetesDestination{Name: "db"},
+			Properties:            []SecretClaimProperty{passwordProperty("password", &PasswordGenerator{Length: 8})},
+		}},
+	}
+	raw, err := json.Marshal(claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+
+	tests := []struct {
+		name   string
+		reader client.Reader
+	}{
+		{name: "no reader"},
+		// The namespace of the claim cannot be found
+		{name: "failed lookup", reader: newPolicyReader(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newClaimValidatingHandler(&SecretClaim{}, tt.reader)
+			if err := handler.InjectDecoder(decoder); err != nil {
+				t.Fatal(err)
+			}
+			resp := handler.Handle(context.Background(), req)
+			if resp.Allowed {
+				t.Fatal("claim was admitted without checking password policies")
+			}
+			if resp.Result.Code != http.StatusInternalServerError {
+				t.Fatalf("got response code %d, want %d", resp.Result.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
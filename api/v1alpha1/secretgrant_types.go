@@ -0,0 +1,90 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretGrantFrom describes the claims that are allowed to write into the grant's namespace
+type SecretGrantFrom struct {
+	// +kubebuilder:validation:Enum=SecretClaim
+	// +kubebuilder:default=SecretClaim
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// SecretGrantTo describes the Secrets in the grant's namespace that may be written
+type SecretGrantTo struct {
+	// Name of the Secret. An empty name allows every Secret in the namespace.
+	Name string `json:"name,omitempty"`
+}
+
+// SecretGrantSpec defines the desired state of SecretGrant
+type SecretGrantSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	From []SecretGrantFrom `json:"from"`
+	// To restricts the Secrets that may be written. An empty list allows every Secret.
+	To []SecretGrantTo `json:"to,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretGrant allows claims in other namespaces to write Secrets into the namespace it lives in.
+// Without a matching grant, cross-namespace destinations are refused.
+type SecretGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretGrantList contains a list of SecretGrant
+type SecretGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretGrant `json:"items"`
+}
+
+// Allows returns true if the grant allows a claim of kind in fromNamespace to write the named Secret.
+func (g *SecretGrant) Allows(kind, fromNamespace, secretName string) bool {
+	fromAllowed := false
+	for _, from := range g.Spec.From {
+		if (from.Kind == "" || from.Kind == kind) && from.Namespace == fromNamespace {
+			fromAllowed = true
+			break
+		}
+	}
+	if !fromAllowed {
+		return false
+	}
+	if len(g.Spec.To) == 0 {
+		return true
+	}
+	for _, to := range g.Spec.To {
+		if to.Name == "" || to.Name == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretGrant{}, &SecretGrantList{})
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -34,12 +36,21 @@ type ValueOrSecretKey struct {
 	SecretRef *SecretRef `json:"secretRef,omitempty"`
 }
 
+// AzureWorkloadIdentity runs the store agent as a service account federated with the
+// Azure AD application in clientId
+type AzureWorkloadIdentity struct {
+	ServiceAccount string `json:"serviceAccount"`
+}
+
 type AzureKeyVaultProviderAuth struct {
-	UseManagedIdentity bool              `json:"useManagedIdentity,omitempty"`
-	SubscriptionId     ValueOrSecretKey  `json:"subscriptionId"`
-	TenantId           ValueOrSecretKey  `json:"tenantId"`
-	ClientId           *ValueOrSecretKey `json:"clientId,omitempty"`
-	ClientSecret       *ValueOrSecretKey `json:"clientSecret,omitempty"`
+	// UseManagedIdentity authenticates with workloadIdentity when set, and with the managed
+	// identity of the instance metadata service otherwise, e.g. through AAD pod identity
+	UseManagedIdentity bool                   `json:"useManagedIdentity,omitempty"`
+	WorkloadIdentity   *AzureWorkloadIdentity `json:"workloadIdentity,omitempty"`
+	SubscriptionId     ValueOrSecretKey       `json:"subscriptionId"`
+	TenantId           ValueOrSecretKey       `json:"tenantId"`
+	ClientId           *ValueOrSecretKey      `json:"clientId,omitempty"`
+	ClientSecret       *ValueOrSecretKey      `json:"clientSecret,omitempty"`
 }
 
 type AzureKeyVaultProvider struct {
@@ -62,23 +73,255 @@ type GcpSecretsManagerProvider struct {
 	Auth      GcpSecretsManagerAuth `json:"auth"`
 }
 
+type VaultTokenAuth struct {
+	Token ValueOrSecretKey `json:"token"`
+}
+
+type VaultAppRoleAuth struct {
+	// Path is the mount path of the AppRole auth method
+	// +kubebuilder:default=approle
+	Path     string           `json:"path,omitempty"`
+	RoleId   ValueOrSecretKey `json:"roleId"`
+	SecretId ValueOrSecretKey `json:"secretId"`
+}
+
+// VaultKubernetesAuth logs in with the token of the service account the store agent runs as,
+// or of the operator in InProcess mode
+type VaultKubernetesAuth struct {
+	// Path is the mount path of the Kubernetes auth method
+	// +kubebuilder:default=kubernetes
+	Path string `json:"path,omitempty"`
+	Role string `json:"role"`
+	// ServiceAccount is created for the store agent and must be bound to the role
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// VaultAuth configures exactly one Vault auth method
+type VaultAuth struct {
+	Token      *VaultTokenAuth      `json:"token,omitempty"`
+	AppRole    *VaultAppRoleAuth    `json:"appRole,omitempty"`
+	Kubernetes *VaultKubernetesAuth `json:"kubernetes,omitempty"`
+}
+
+// VaultProvider stores secrets in a HashiCorp Vault KV version 2 secrets engine
+type VaultProvider struct {
+	// Server is the address of Vault, e.g. https://vault.vault.svc:8200
+	Server string `json:"server"`
+	// Namespace is the Vault Enterprise namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Mount is the mount path of the KV version 2 secrets engine
+	// +kubebuilder:default=secret
+	Mount string `json:"mount,omitempty"`
+	// Path is prepended to the names of the secrets
+	Path string `json:"path,omitempty"`
+	// CABundle is the PEM encoded CA bundle used to verify the server certificate
+	CABundle string    `json:"caBundle,omitempty"`
+	Auth     VaultAuth `json:"auth"`
+}
+
+// AwsIrsa runs the store agent as a service account annotated with an IAM role (IAM Roles for Service Accounts)
+type AwsIrsa struct {
+	ServiceAccount string `json:"serviceAccount"`
+	RoleArn        string `json:"roleArn"`
+}
+
+// AwsAssumeRole assumes a role with the credentials configured by the other auth fields
+type AwsAssumeRole struct {
+	RoleArn     string `json:"roleArn"`
+	ExternalId  string `json:"externalId,omitempty"`
+	SessionName string `json:"sessionName,omitempty"`
+}
+
+// AwsAuth configures the AWS credentials. Without static keys or irsa the default
+// credential chain of the store agent, or of the operator in InProcess mode, is used.
+type AwsAuth struct {
+	AccessKeyId     *ValueOrSecretKey `json:"accessKeyId,omitempty"`
+	SecretAccessKey *ValueOrSecretKey `json:"secretAccessKey,omitempty"`
+	Irsa            *AwsIrsa          `json:"irsa,omitempty"`
+	AssumeRole      *AwsAssumeRole    `json:"assumeRole,omitempty"`
+}
+
+type AwsSecretsManagerProvider struct {
+	Region string `json:"region"`
+	// Endpoint overrides the AWS endpoints, e.g. for a local emulator
+	Endpoint string `json:"endpoint,omitempty"`
+	// KmsKeyId encrypts secrets created by the operator instead of the account's default key
+	KmsKeyId string  `json:"kmsKeyId,omitempty"`
+	Auth     AwsAuth `json:"auth,omitempty"`
+}
+
+// AwsParameterStoreProvider stores secrets as SecureString parameters below Path
+type AwsParameterStoreProvider struct {
+	Region string `json:"region"`
+	// Path is the parameter hierarchy, e.g. /app/production
+	// +kubebuilder:default=/
+	Path string `json:"path,omitempty"`
+	// Endpoint overrides the AWS endpoints, e.g. for a local emulator
+	Endpoint string `json:"endpoint,omitempty"`
+	// KmsKeyId encrypts parameters instead of the account's default key
+	KmsKeyId string  `json:"kmsKeyId,omitempty"`
+	Auth     AwsAuth `json:"auth,omitempty"`
+}
+
+// KubernetesAuth connects to the remote API server with a kubeconfig, or with a server
+// address, a service account token and the CA of the server. The kubeconfig may only use
+// inline data, file paths, exec and auth providers are rejected.
+type KubernetesAuth struct {
+	Kubeconfig *ValueOrSecretKey `json:"kubeconfig,omitempty"`
+	// Server is the address of the API server, e.g. https://secrets.example.com:6443
+	Server   string            `json:"server,omitempty"`
+	Token    *ValueOrSecretKey `json:"token,omitempty"`
+	CABundle *ValueOrSecretKey `json:"caBundle,omitempty"`
+}
+
+// KubernetesProvider stores secrets as Secrets in a namespace of a remote cluster
+type KubernetesProvider struct {
+	Namespace string `json:"namespace"`
+	// Key is the data key of the Secret holding the value
+	// +kubebuilder:default=value
+	Key  string         `json:"key,omitempty"`
+	Auth KubernetesAuth `json:"auth"`
+}
+
+// SopsConfigMap holds SOPS documents, one per .yaml or .yml data key
+type SopsConfigMap struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// SopsKeys decrypt the data keys of the documents
+type SopsKeys struct {
+	// Age is an age identity file, as written by age-keygen
+	Age *ValueOrSecretKey `json:"age,omitempty"`
+	// Pgp is an armored PGP private key without passphrase
+	Pgp *ValueOrSecretKey `json:"pgp,omitempty"`
+}
+
+// SopsProvider reads secrets from SOPS encrypted YAML documents in a ConfigMap or a directory.
+// A secret is named <document>/<key>, where document is the file name without extension and
+// nested keys are separated by slashes. Written secrets are re-encrypted into the document.
+type SopsProvider struct {
+	ConfigMap *SopsConfigMap `json:"configMap,omitempty"`
+	// Path is a directory of documents in the store agent, e.g. a volume added with deployment overrides
+	Path string   `json:"path,omitempty"`
+	Keys SopsKeys `json:"keys"`
+	// AgeRecipients can decrypt documents created by the operator, in addition to the configured keys
+	AgeRecipients []string `json:"ageRecipients,omitempty"`
+	// ServiceAccount runs the store agent as a service account, which must be allowed to get
+	// and update the ConfigMap
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// FakeConfigMap persists the secrets of a fake store
+type FakeConfigMap struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// FakeProvider keeps secrets in memory, for development clusters and tests. Fake stores with
+// the same configuration share their secrets within a process.
+type FakeProvider struct {
+	// Data seeds the store with secrets by name
+	Data map[string]string `json:"data,omitempty"`
+	// ConfigMap persists the secrets, so they survive restarts of the store. It is created
+	// with the seeded data if it does not exist.
+	ConfigMap *FakeConfigMap `json:"configMap,omitempty"`
+	// ServiceAccount runs the store agent as a service account, which must be allowed to get,
+	// create and update the ConfigMap
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
 type Provider struct {
 	AzureKeyVault     *AzureKeyVaultProvider     `json:"azureKeyVault,omitempty"`
 	GcpSecretsManager *GcpSecretsManagerProvider `json:"gsm,omitempty"`
+	Vault             *VaultProvider             `json:"vault,omitempty"`
+	AwsSecretsManager *AwsSecretsManagerProvider `json:"awsSecretsManager,omitempty"`
+	AwsParameterStore *AwsParameterStoreProvider `json:"awsParameterStore,omitempty"`
+	Kubernetes        *KubernetesProvider        `json:"kubernetes,omitempty"`
+	Sops              *SopsProvider              `json:"sops,omitempty"`
+	Fake              *FakeProvider              `json:"fake,omitempty"`
 }
 
+// StoreDeployment overrides the pod template of the store deployment
+type StoreDeployment struct {
+	// Image replaces the operator-wide default store image
+	Image            string                        `json:"image,omitempty"`
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	Replicas        *int32                      `json:"replicas,omitempty"`
+	Resources       corev1.ResourceRequirements `json:"resources,omitempty"`
+	NodeSelector    map[string]string           `json:"nodeSelector,omitempty"`
+	Tolerations     []corev1.Toleration         `json:"tolerations,omitempty"`
+	Affinity        *corev1.Affinity            `json:"affinity,omitempty"`
+	SecurityContext *corev1.SecurityContext     `json:"securityContext,omitempty"`
+	// PodSecurityContext is applied to the store pod, SecurityContext to its container
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Env is added to the store container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Annotations are added to the store pod
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// StoreMode selects where the provider of a store is called from
+type StoreMode string
+
+const (
+	// StoreModeAgent runs a store agent deployment per store
+	StoreModeAgent StoreMode = "Agent"
+	// StoreModeInProcess calls the provider from the operator itself
+	StoreModeInProcess StoreMode = "InProcess"
+)
+
 // SecretStoreSpec defines the desired state of SecretStore
 type SecretStoreSpec struct {
 	Provider Provider `json:"provider"`
+	// Mode defaults to the operator's --store-mode flag. Only a ClusterSecretStore may set InProcess,
+	// namespaced stores in InProcess mode may not use the operator's identity to authenticate.
+	// +kubebuilder:validation:Enum=Agent;InProcess
+	Mode StoreMode `json:"mode,omitempty"`
+	// Deployment overrides the store agent deployment. It is ignored in InProcess mode.
+	Deployment *StoreDeployment `json:"deployment,omitempty"`
+}
+
+// ModeOr returns the mode of the store, or defaultMode if the store does not set one
+func (s *SecretStoreSpec) ModeOr(defaultMode StoreMode) StoreMode {
+	if s.Mode == "" {
+		return defaultMode
+	}
+	return s.Mode
+}
+
+const (
+	SecretStoreKind        = "SecretStore"
+	ClusterSecretStoreKind = "ClusterSecretStore"
+)
+
+// SecretStoreRef references a SecretStore in the claim's namespace or a ClusterSecretStore
+type SecretStoreRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	// +kubebuilder:default=SecretStore
+	Kind string `json:"kind,omitempty"`
+}
+
+// GenericStore is implemented by both SecretStore and ClusterSecretStore.
+// +kubebuilder:object:generate=false
+type GenericStore interface {
+	metav1.Object
+	runtime.Object
+	GetSpec() *SecretStoreSpec
+	GetStatus() *SecretStoreStatus
 }
 
 // SecretStoreStatus defines the observed state of SecretStore
 type SecretStoreStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Ready is false while the store cannot be deployed, e.g. because of malformed credentials
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // SecretStore is the Schema for the secretstores API
 type SecretStore struct {
@@ -98,6 +341,16 @@ type SecretStoreList struct {
 	Items           []SecretStore `json:"items"`
 }
 
+// GetSpec returns the provider configuration of the store
+func (s *SecretStore) GetSpec() *SecretStoreSpec {
+	return &s.Spec
+}
+
+// GetStatus returns the observed state of the store
+func (s *SecretStore) GetStatus() *SecretStoreStatus {
+	return &s.Status
+}
+
 func init() {
 	SchemeBuilder.Register(&SecretStore{}, &SecretStoreList{})
 }
@@ -0,0 +1,276 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var secretstorelog = logf.Log.WithName("secretstore-resource")
+
+func (r *SecretStore) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-secret-operator-io-v1alpha1-secretstore,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=secret-operator.io,resources=secretstores,versions=v1alpha1,name=vsecretstore.secret-operator.io
+
+var _ webhook.Validator = &SecretStore{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretStore) ValidateCreate() error {
+	secretstorelog.Info("validate create", "name", r.Name)
+
+	return invalidError(SecretStoreKind, r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretStore) ValidateUpdate(old runtime.Object) error {
+	secretstorelog.Info("validate update", "name", r.Name)
+
+	return invalidError(SecretStoreKind, r.Name, r.validate())
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretStore) ValidateDelete() error {
+	return nil
+}
+
+func (r *SecretStore) validate() field.ErrorList {
+	allErrs := validateProvider(&r.Spec.Provider, field.NewPath("spec", "provider"))
+	allErrs = append(allErrs, r.validateMode()...)
+	return append(allErrs, r.ValidateNamespaces()...)
+}
+
+// ValidateNamespaces checks that the store only references Secrets and ConfigMaps in its own
+// namespace. It is checked by the reconcilers before anything is resolved, so it also holds
+// without the webhook.
+func (r *SecretStore) ValidateNamespaces() field.ErrorList {
+	var allErrs field.ErrorList
+	providerPath := field.NewPath("spec", "provider")
+	walkSecretRefs(reflect.ValueOf(r.Spec.Provider), providerPath, func(ref SecretRef, fldPath *field.Path) {
+		if ref.Namespace != r.Namespace {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), ref.Namespace, "must be the namespace of the store"))
+		}
+	})
+	if sops := r.Spec.Provider.Sops; sops != nil && sops.ConfigMap != nil && sops.ConfigMap.Namespace != r.Namespace {
+		allErrs = append(allErrs, field.Invalid(providerPath.Child("sops", "configMap", "namespace"), sops.ConfigMap.Namespace, "must be the namespace of the store"))
+	}
+	if fake := r.Spec.Provider.Fake; fake != nil && fake.ConfigMap != nil && fake.ConfigMap.Namespace != r.Namespace {
+		allErrs = append(allErrs, field.Invalid(providerPath.Child("fake", "configMap", "namespace"), fake.ConfigMap.Namespace, "must be the namespace of the store"))
+	}
+	return allErrs
+}
+
+// ValidateInProcess checks that the store may be called from the operator when it runs in
+// InProcess mode, with defaultMode applying if the store does not set one. Namespaced stores may not
+// select InProcess mode themselves and may not authenticate with the operator's identity, e.g. its
+// service account token or cloud workload identity. Like ValidateNamespaces, it is enforced by
+// the reconcilers.
+func (r *SecretStore) ValidateInProcess(defaultMode StoreMode) field.ErrorList {
+	allErrs := r.validateMode()
+	if r.Spec.ModeOr(defaultMode) == StoreModeInProcess {
+		allErrs = append(allErrs, validateAmbientCredentials(&r.Spec.Provider, field.NewPath("spec", "provider"))...)
+	}
+	return allErrs
+}
+
+// validateMode restricts the InProcess mode to ClusterSecretStores, namespaced stores only run
+// in process if it is the operator's default.
+func (r *SecretStore) validateMode() field.ErrorList {
+	if r.Spec.Mode != StoreModeInProcess {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec", "mode"), "only a ClusterSecretStore may select InProcess mode")}
+}
+
+// ProviderValidator validates the configuration of one provider, see RegisterProviderValidator
+// +kubebuilder:object:generate=false
+type ProviderValidator func(provider *Provider, fldPath *field.Path) field.ErrorList
+
+var providerValidators = map[string]ProviderValidator{}
+
+// RegisterProviderValidator registers the validation of the provider configured by the
+// spec.provider field name. Providers register themselves with the provider registry.
+func RegisterProviderValidator(name string, validate ProviderValidator) {
+	providerValidators[name] = validate
+}
+
+var ambientCredentialsChecks = map[string]ProviderValidator{}
+
+// RegisterAmbientCredentialsCheck registers the check of the provider configured by the
+// spec.provider field name that reports the fields authenticating with the identity of the
+// process calling the provider. Providers register themselves with the provider registry.
+func RegisterAmbientCredentialsCheck(name string, check ProviderValidator) {
+	ambientCredentialsChecks[name] = check
+}
+
+// ConfiguredProviders returns the field names of the providers set in provider
+func ConfiguredProviders(provider *Provider) []string {
+	raw, err := json.Marshal(provider)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SecretRefs returns the secretRef of every ValueOrSecretKey configured in provider
+func SecretRefs(provider *Provider) []SecretRef {
+	var refs []SecretRef
+	walkSecretRefs(reflect.ValueOf(*provider), field.NewPath("provider"), func(ref SecretRef, _ *field.Path) {
+		refs = append(refs, ref)
+	})
+	return refs
+}
+
+// ReplaceInlineValues replaces the value of every ValueOrSecretKey configured inline in provider
+// with the secretRef returned by ref for its field path. It returns the replaced values by secretRef.
+func ReplaceInlineValues(provider *Provider, ref func(fldPath *field.Path) SecretRef) map[SecretRef]string {
+	values := map[SecretRef]string{}
+	walkInlineValues(reflect.ValueOf(provider).Elem(), field.NewPath("provider"), func(v *ValueOrSecretKey, fldPath *field.Path) {
+		secretRef := ref(fldPath)
+		values[secretRef] = *v.Value
+		v.Value, v.SecretRef = nil, &secretRef
+	})
+	return values
+}
+
+var valueOrSecretKeyType = reflect.TypeOf(ValueOrSecretKey{})
+
+// walkInlineValues calls fn with every ValueOrSecretKey holding a value below the addressable v
+func walkInlineValues(v reflect.Value, fldPath *field.Path, fn func(v *ValueOrSecretKey, fldPath *field.Path)) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkInlineValues(v.Elem(), fldPath, fn)
+		}
+	case reflect.Struct:
+		if v.Type() == valueOrSecretKeyType {
+			if value := v.Addr().Interface().(*ValueOrSecretKey); value.Value != nil {
+				fn(value, fldPath)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+			walkInlineValues(v.Field(i), fldPath.Child(name), fn)
+		}
+	}
+}
+
+// walkSecretRefs calls fn with the secretRef of every ValueOrSecretKey below v and its path
+func walkSecretRefs(v reflect.Value, fldPath *field.Path, fn func(ref SecretRef, fldPath *field.Path)) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkSecretRefs(v.Elem(), fldPath, fn)
+		}
+	case reflect.Struct:
+		if v.Type() == valueOrSecretKeyType {
+			if ref := v.Interface().(ValueOrSecretKey).SecretRef; ref != nil {
+				fn(*ref, fldPath.Child("secretRef"))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+			walkSecretRefs(v.Field(i), fldPath.Child(name), fn)
+		}
+	}
+}
+
+func validateProvider(provider *Provider, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := ConfiguredProviders(provider)
+	switch {
+	case len(names) == 0:
+		allErrs = append(allErrs, field.Required(fldPath, "a provider must be set"))
+	case len(names) > 1:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one provider may be set"))
+	}
+	for _, name := range names {
+		validate, ok := providerValidators[name]
+		if !ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(name), "provider is not supported by this operator"))
+			continue
+		}
+		allErrs = append(allErrs, validate(provider, fldPath.Child(name))...)
+	}
+	return allErrs
+}
+
+func validateAmbientCredentials(provider *Provider, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, name := range ConfiguredProviders(provider) {
+		if check, ok := ambientCredentialsChecks[name]; ok {
+			allErrs = append(allErrs, check(provider, fldPath.Child(name))...)
+		}
+	}
+	return allErrs
+}
+
+// ValidateValueOrSecretKey checks that exactly one of value or secretRef is set
+func ValidateValueOrSecretKey(v *ValueOrSecretKey, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case v.Value == nil && v.SecretRef == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "one of value or secretRef must be set"))
+	case v.Value != nil && v.SecretRef != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of value or secretRef may be set"))
+	}
+	if v.SecretRef != nil {
+		refPath := fldPath.Child("secretRef")
+		if v.SecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("name"), "secret name must be set"))
+		}
+		if v.SecretRef.Key == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("key"), "secret key must be set"))
+		}
+	}
+	return allErrs
+}
+
+// invalidError wraps a non-empty field.ErrorList in the Invalid status error returned to the API server.
+func invalidError(kind, name string, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: kind}, name, allErrs)
+}
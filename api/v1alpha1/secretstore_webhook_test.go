@@ -0,0 +1,219 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	// Providers register their validation with the api package
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/all"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func TestSecretStoreValidateCreate(t *testing.T) {
+	vault := func(token v1alpha1.ValueOrSecretKey) *v1alpha1.VaultProvider {
+		return &v1alpha1.VaultProvider{
+			Server: "https://vault.vault.svc:8200",
+			Auth:   v1alpha1.VaultAuth{Token: &v1alpha1.VaultTokenAuth{Token: token}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		provider v1alpha1.Provider
+		want     []string
+	}{
+		{
+			name:     "valid provider",
+			provider: v1alpha1.Provider{Fake: &v1alpha1.FakeProvider{}},
+		},
+		{
+			name:     "no provider",
+			provider: v1alpha1.Provider{},
+			want:     []string{"spec.provider"},
+		},
+		{
+			name: "two providers",
+			provider: v1alpha1.Provider{
+				Fake:  &v1alpha1.FakeProvider{},
+				Vault: vault(v1alpha1.ValueOrSecretKey{Value: stringPtr("token")}),
+			},
+			want: []string{"spec.provider"},
+		},
+		{
+			name: "secretRef in the namespace of the store",
+			provider: v1alpha1.Provider{Vault: vault(v1alpha1.ValueOrSecretKey{
+				SecretRef: &v1alpha1.SecretRef{Namespace: "default", Name: "vault", Key: "token"},
+			})},
+		},
+		{
+			name: "secretRef in another namespace",
+			provider: v1alpha1.Provider{Vault: vault(v1alpha1.ValueOrSecretKey{
+				SecretRef: &v1alpha1.SecretRef{Namespace: "kube-system", Name: "vault", Key: "token"},
+			})},
+			want: []string{"spec.provider.vault.auth.token.token.secretRef.namespace"},
+		},
+		{
+			name: "fake configMap in another namespace",
+			provider: v1alpha1.Provider{Fake: &v1alpha1.FakeProvider{
+				ConfigMap: &v1alpha1.FakeConfigMap{Namespace: "kube-system", Name: "fake-store"},
+			}},
+			want: []string{"spec.provider.fake.configMap.namespace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &v1alpha1.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+				Spec:       v1alpha1.SecretStoreSpec{Provider: tt.provider},
+			}
+			err := store.ValidateCreate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			statusErr, ok := err.(*apierrors.StatusError)
+			if !ok {
+				t.Fatalf("got %v, want an Invalid status error", err)
+			}
+			causes := statusErr.ErrStatus.Details.Causes
+			if len(causes) != len(tt.want) {
+				t.Fatalf("got causes %v, want errors on %v", causes, tt.want)
+			}
+			for i, cause := range causes {
+				if cause.Field != tt.want[i] {
+					t.Fatalf("got causes %v, want errors on %v", causes, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretStoreValidateInProcess(t *testing.T) {
+	secretRef := func(name string) *v1alpha1.ValueOrSecretKey {
+		return &v1alpha1.ValueOrSecretKey{SecretRef: &v1alpha1.SecretRef{Namespace: "default", Name: name, Key: "value"}}
+	}
+	vaultKubernetes := v1alpha1.Provider{Vault: &v1alpha1.VaultProvider{
+		Server: "https://vault.vault.svc:8200",
+		Auth:   v1alpha1.VaultAuth{Kubernetes: &v1alpha1.VaultKubernetesAuth{Role: "tenant"}},
+	}}
+
+	tests := []struct {
+		name        string
+		mode        v1alpha1.StoreMode
+		defaultMode v1alpha1.StoreMode
+		provider    v1alpha1.Provider
+		want        []string
+	}{
+		{
+			name:        "agent mode",
+			defaultMode: v1alpha1.StoreModeAgent,
+			provider:    vaultKubernetes,
+		},
+		{
+			name:        "InProcess selected by the store",
+			mode:        v1alpha1.StoreModeInProcess,
+			defaultMode: v1alpha1.StoreModeAgent,
+			provider:    v1alpha1.Provider{Fake: &v1alpha1.FakeProvider{}},
+			want:        []string{"spec.mode"},
+		},
+		{
+			name:        "vault kubernetes auth",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider:    vaultKubernetes,
+			want:        []string{"spec.provider.vault.auth.kubernetes"},
+		},
+		{
+			name:        "aws default credential chain",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider:    v1alpha1.Provider{AwsSecretsManager: &v1alpha1.AwsSecretsManagerProvider{Region: "eu-west-1"}},
+			want:        []string{"spec.provider.awsSecretsManager.auth.accessKeyId"},
+		},
+		{
+			name:        "aws static keys",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider: v1alpha1.Provider{AwsParameterStore: &v1alpha1.AwsParameterStoreProvider{
+				Region: "eu-west-1",
+				Auth:   v1alpha1.AwsAuth{AccessKeyId: secretRef("aws"), SecretAccessKey: secretRef("aws")},
+			}},
+		},
+		{
+			name:        "gcp workload identity",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider: v1alpha1.Provider{GcpSecretsManager: &v1alpha1.GcpSecretsManagerProvider{
+				ProjectId: "project",
+				Auth: v1alpha1.GcpSecretsManagerAuth{WorkloadIdentity: &v1alpha1.GcpWorkloadIdentity{
+					ServiceAccount: "store", GcpServiceAccount: "store",
+				}},
+			}},
+			want: []string{"spec.provider.gsm.auth.credentialsFile"},
+		},
+		{
+			name:        "azure managed identity",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider: v1alpha1.Provider{AzureKeyVault: &v1alpha1.AzureKeyVaultProvider{
+				VaultName: "vault",
+				Auth:      v1alpha1.AzureKeyVaultProviderAuth{UseManagedIdentity: true},
+			}},
+			want: []string{"spec.provider.azureKeyVault.auth.useManagedIdentity"},
+		},
+		{
+			name:        "kubernetes without credentials",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider:    v1alpha1.Provider{Kubernetes: &v1alpha1.KubernetesProvider{Namespace: "secrets"}},
+			want:        []string{"spec.provider.kubernetes.auth"},
+		},
+		{
+			name:        "kubernetes kubeconfig",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider: v1alpha1.Provider{Kubernetes: &v1alpha1.KubernetesProvider{
+				Namespace: "secrets",
+				Auth:      v1alpha1.KubernetesAuth{Kubeconfig: secretRef("kubeconfig")},
+			}},
+		},
+		{
+			name:        "sops path",
+			defaultMode: v1alpha1.StoreModeInProcess,
+			provider:    v1alpha1.Provider{Sops: &v1alpha1.SopsProvider{Path: "/etc/secrets"}},
+			want:        []string{"spec.provider.sops.path"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &v1alpha1.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+				Spec:       v1alpha1.SecretStoreSpec{Provider: tt.provider, Mode: tt.mode},
+			}
+			allErrs := store.ValidateInProcess(tt.defaultMode)
+			if len(allErrs) != len(tt.want) {
+				t.Fatalf("got errors %v, want errors on %v", allErrs, tt.want)
+			}
+			for i, err := range allErrs {
+				if err.Field != tt.want[i] {
+					t.Fatalf("got errors %v, want errors on %v", allErrs, tt.want)
+				}
+			}
+		})
+	}
+}
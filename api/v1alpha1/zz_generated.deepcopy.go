@@ -21,245 +21,192 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureKeyVaultProvider) DeepCopyInto(out *AzureKeyVaultProvider) {
+func (in *AwsAssumeRole) DeepCopyInto(out *AwsAssumeRole) {
 	*out = *in
-	in.Auth.DeepCopyInto(&out.Auth)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultProvider.
-func (in *AzureKeyVaultProvider) DeepCopy() *AzureKeyVaultProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsAssumeRole.
+func (in *AwsAssumeRole) DeepCopy() *AwsAssumeRole {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureKeyVaultProvider)
+	out := new(AwsAssumeRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureKeyVaultProviderAuth) DeepCopyInto(out *AzureKeyVaultProviderAuth) {
+func (in *AwsAuth) DeepCopyInto(out *AwsAuth) {
 	*out = *in
-	in.SubscriptionId.DeepCopyInto(&out.SubscriptionId)
-	in.TenantId.DeepCopyInto(&out.TenantId)
-	if in.ClientId != nil {
-		in, out := &in.ClientId, &out.ClientId
+	if in.AccessKeyId != nil {
+		in, out := &in.AccessKeyId, &out.AccessKeyId
 		*out = new(ValueOrSecretKey)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ClientSecret != nil {
-		in, out := &in.ClientSecret, &out.ClientSecret
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
 		*out = new(ValueOrSecretKey)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultProviderAuth.
-func (in *AzureKeyVaultProviderAuth) DeepCopy() *AzureKeyVaultProviderAuth {
-	if in == nil {
-		return nil
-	}
-	out := new(AzureKeyVaultProviderAuth)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GcpSecretsManagerAuth) DeepCopyInto(out *GcpSecretsManagerAuth) {
-	*out = *in
-	if in.WorkloadIdentity != nil {
-		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
-		*out = new(GcpWorkloadIdentity)
+	if in.Irsa != nil {
+		in, out := &in.Irsa, &out.Irsa
+		*out = new(AwsIrsa)
 		**out = **in
 	}
-	if in.CredentialsFile != nil {
-		in, out := &in.CredentialsFile, &out.CredentialsFile
-		*out = new(ValueOrSecretKey)
-		(*in).DeepCopyInto(*out)
+	if in.AssumeRole != nil {
+		in, out := &in.AssumeRole, &out.AssumeRole
+		*out = new(AwsAssumeRole)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpSecretsManagerAuth.
-func (in *GcpSecretsManagerAuth) DeepCopy() *GcpSecretsManagerAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsAuth.
+func (in *AwsAuth) DeepCopy() *AwsAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(GcpSecretsManagerAuth)
+	out := new(AwsAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GcpSecretsManagerProvider) DeepCopyInto(out *GcpSecretsManagerProvider) {
+func (in *AwsIrsa) DeepCopyInto(out *AwsIrsa) {
 	*out = *in
-	in.Auth.DeepCopyInto(&out.Auth)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpSecretsManagerProvider.
-func (in *GcpSecretsManagerProvider) DeepCopy() *GcpSecretsManagerProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsIrsa.
+func (in *AwsIrsa) DeepCopy() *AwsIrsa {
 	if in == nil {
 		return nil
 	}
-	out := new(GcpSecretsManagerProvider)
+	out := new(AwsIrsa)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GcpWorkloadIdentity) DeepCopyInto(out *GcpWorkloadIdentity) {
+func (in *AwsParameterStoreProvider) DeepCopyInto(out *AwsParameterStoreProvider) {
 	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpWorkloadIdentity.
-func (in *GcpWorkloadIdentity) DeepCopy() *GcpWorkloadIdentity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsParameterStoreProvider.
+func (in *AwsParameterStoreProvider) DeepCopy() *AwsParameterStoreProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(GcpWorkloadIdentity)
+	out := new(AwsParameterStoreProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubernetesClaim) DeepCopyInto(out *KubernetesClaim) {
+func (in *AwsSecretsManagerProvider) DeepCopyInto(out *AwsSecretsManagerProvider) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make([]SecretClaimProperty, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Auth.DeepCopyInto(&out.Auth)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClaim.
-func (in *KubernetesClaim) DeepCopy() *KubernetesClaim {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsSecretsManagerProvider.
+func (in *AwsSecretsManagerProvider) DeepCopy() *AwsSecretsManagerProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(KubernetesClaim)
+	out := new(AwsSecretsManagerProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PasswordGenerator) DeepCopyInto(out *PasswordGenerator) {
+func (in *AzureKeyVaultProvider) DeepCopyInto(out *AzureKeyVaultProvider) {
 	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordGenerator.
-func (in *PasswordGenerator) DeepCopy() *PasswordGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultProvider.
+func (in *AzureKeyVaultProvider) DeepCopy() *AzureKeyVaultProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(PasswordGenerator)
+	out := new(AzureKeyVaultProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PropertyGenerator) DeepCopyInto(out *PropertyGenerator) {
+func (in *AzureKeyVaultProviderAuth) DeepCopyInto(out *AzureKeyVaultProviderAuth) {
 	*out = *in
-	if in.Password != nil {
-		in, out := &in.Password, &out.Password
-		*out = new(PasswordGenerator)
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(AzureWorkloadIdentity)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyGenerator.
-func (in *PropertyGenerator) DeepCopy() *PropertyGenerator {
-	if in == nil {
-		return nil
+	in.SubscriptionId.DeepCopyInto(&out.SubscriptionId)
+	in.TenantId.DeepCopyInto(&out.TenantId)
+	if in.ClientId != nil {
+		in, out := &in.ClientId, &out.ClientId
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(PropertyGenerator)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PropertySource) DeepCopyInto(out *PropertySource) {
-	*out = *in
-	if in.PropertyGenerator != nil {
-		in, out := &in.PropertyGenerator, &out.PropertyGenerator
-		*out = new(PropertyGenerator)
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(ValueOrSecretKey)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertySource.
-func (in *PropertySource) DeepCopy() *PropertySource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultProviderAuth.
+func (in *AzureKeyVaultProviderAuth) DeepCopy() *AzureKeyVaultProviderAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(PropertySource)
+	out := new(AzureKeyVaultProviderAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Provider) DeepCopyInto(out *Provider) {
+func (in *AzureWorkloadIdentity) DeepCopyInto(out *AzureWorkloadIdentity) {
 	*out = *in
-	if in.AzureKeyVault != nil {
-		in, out := &in.AzureKeyVault, &out.AzureKeyVault
-		*out = new(AzureKeyVaultProvider)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GcpSecretsManager != nil {
-		in, out := &in.GcpSecretsManager, &out.GcpSecretsManager
-		*out = new(GcpSecretsManagerProvider)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
-func (in *Provider) DeepCopy() *Provider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureWorkloadIdentity.
+func (in *AzureWorkloadIdentity) DeepCopy() *AzureWorkloadIdentity {
 	if in == nil {
 		return nil
 	}
-	out := new(Provider)
+	out := new(AzureWorkloadIdentity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretClaim) DeepCopyInto(out *SecretClaim) {
+func (in *ClusterSecretClaim) DeepCopyInto(out *ClusterSecretClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaim.
-func (in *SecretClaim) DeepCopy() *SecretClaim {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretClaim.
+func (in *ClusterSecretClaim) DeepCopy() *ClusterSecretClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretClaim)
+	out := new(ClusterSecretClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretClaim) DeepCopyObject() runtime.Object {
+func (in *ClusterSecretClaim) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -267,31 +214,31 @@ func (in *SecretClaim) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretClaimList) DeepCopyInto(out *SecretClaimList) {
+func (in *ClusterSecretClaimList) DeepCopyInto(out *ClusterSecretClaimList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SecretClaim, len(*in))
+		*out = make([]ClusterSecretClaim, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimList.
-func (in *SecretClaimList) DeepCopy() *SecretClaimList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretClaimList.
+func (in *ClusterSecretClaimList) DeepCopy() *ClusterSecretClaimList {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretClaimList)
+	out := new(ClusterSecretClaimList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretClaimList) DeepCopyObject() runtime.Object {
+func (in *ClusterSecretClaimList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -299,73 +246,55 @@ func (in *SecretClaimList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretClaimProperty) DeepCopyInto(out *SecretClaimProperty) {
-	*out = *in
-	in.PropertySource.DeepCopyInto(&out.PropertySource)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimProperty.
-func (in *SecretClaimProperty) DeepCopy() *SecretClaimProperty {
-	if in == nil {
-		return nil
-	}
-	out := new(SecretClaimProperty)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretClaimSpec) DeepCopyInto(out *SecretClaimSpec) {
+func (in *ClusterSecretClaimSpec) DeepCopyInto(out *ClusterSecretClaimSpec) {
 	*out = *in
-	if in.KubernetesClaim != nil {
-		in, out := &in.KubernetesClaim, &out.KubernetesClaim
-		*out = new(KubernetesClaim)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimSpec.
-func (in *SecretClaimSpec) DeepCopy() *SecretClaimSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretClaimSpec.
+func (in *ClusterSecretClaimSpec) DeepCopy() *ClusterSecretClaimSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretClaimSpec)
+	out := new(ClusterSecretClaimSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretClaimStatus) DeepCopyInto(out *SecretClaimStatus) {
+func (in *ClusterSecretClaimStatus) DeepCopyInto(out *ClusterSecretClaimStatus) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimStatus.
-func (in *SecretClaimStatus) DeepCopy() *SecretClaimStatus {
-	if in == nil {
-		return nil
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(SecretClaimStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretRef) DeepCopyInto(out *SecretRef) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRef.
-func (in *SecretRef) DeepCopy() *SecretRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretClaimStatus.
+func (in *ClusterSecretClaimStatus) DeepCopy() *ClusterSecretClaimStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretRef)
+	out := new(ClusterSecretClaimStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStore) DeepCopyInto(out *SecretStore) {
+func (in *ClusterSecretStore) DeepCopyInto(out *ClusterSecretStore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -373,18 +302,18 @@ func (in *SecretStore) DeepCopyInto(out *SecretStore) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStore.
-func (in *SecretStore) DeepCopy() *SecretStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretStore.
+func (in *ClusterSecretStore) DeepCopy() *ClusterSecretStore {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretStore)
+	out := new(ClusterSecretStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretStore) DeepCopyObject() runtime.Object {
+func (in *ClusterSecretStore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -392,31 +321,31 @@ func (in *SecretStore) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStoreList) DeepCopyInto(out *SecretStoreList) {
+func (in *ClusterSecretStoreList) DeepCopyInto(out *ClusterSecretStoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SecretStore, len(*in))
+		*out = make([]ClusterSecretStore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreList.
-func (in *SecretStoreList) DeepCopy() *SecretStoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretStoreList.
+func (in *ClusterSecretStoreList) DeepCopy() *ClusterSecretStoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretStoreList)
+	out := new(ClusterSecretStoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretStoreList) DeepCopyObject() runtime.Object {
+func (in *ClusterSecretStoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -424,57 +353,1146 @@ func (in *SecretStoreList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
+func (in *ClusterSecretStoreSpec) DeepCopyInto(out *ClusterSecretStoreSpec) {
 	*out = *in
-	in.Provider.DeepCopyInto(&out.Provider)
+	in.SecretStoreSpec.DeepCopyInto(&out.SecretStoreSpec)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreSpec.
-func (in *SecretStoreSpec) DeepCopy() *SecretStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretStoreSpec.
+func (in *ClusterSecretStoreSpec) DeepCopy() *ClusterSecretStoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretStoreSpec)
+	out := new(ClusterSecretStoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStoreStatus) DeepCopyInto(out *SecretStoreStatus) {
+func (in *ClusterSecretStoreStatus) DeepCopyInto(out *ClusterSecretStoreStatus) {
 	*out = *in
+	out.SecretStoreStatus = in.SecretStoreStatus
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreStatus.
-func (in *SecretStoreStatus) DeepCopy() *SecretStoreStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretStoreStatus.
+func (in *ClusterSecretStoreStatus) DeepCopy() *ClusterSecretStoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretStoreStatus)
+	out := new(ClusterSecretStoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ValueOrSecretKey) DeepCopyInto(out *ValueOrSecretKey) {
+func (in *DestinationStatus) DeepCopyInto(out *DestinationStatus) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
-		*out = new(string)
-		**out = **in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
 	}
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(SecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationStatus.
+func (in *DestinationStatus) DeepCopy() *DestinationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FakeConfigMap) DeepCopyInto(out *FakeConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FakeConfigMap.
+func (in *FakeConfigMap) DeepCopy() *FakeConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(FakeConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FakeProvider) DeepCopyInto(out *FakeProvider) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(FakeConfigMap)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueOrSecretKey.
-func (in *ValueOrSecretKey) DeepCopy() *ValueOrSecretKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FakeProvider.
+func (in *FakeProvider) DeepCopy() *FakeProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(ValueOrSecretKey)
+	out := new(FakeProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GcpSecretsManagerAuth) DeepCopyInto(out *GcpSecretsManagerAuth) {
+	*out = *in
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(GcpWorkloadIdentity)
+		**out = **in
+	}
+	if in.CredentialsFile != nil {
+		in, out := &in.CredentialsFile, &out.CredentialsFile
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpSecretsManagerAuth.
+func (in *GcpSecretsManagerAuth) DeepCopy() *GcpSecretsManagerAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(GcpSecretsManagerAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GcpSecretsManagerProvider) DeepCopyInto(out *GcpSecretsManagerProvider) {
+	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpSecretsManagerProvider.
+func (in *GcpSecretsManagerProvider) DeepCopy() *GcpSecretsManagerProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GcpSecretsManagerProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GcpWorkloadIdentity) DeepCopyInto(out *GcpWorkloadIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcpWorkloadIdentity.
+func (in *GcpWorkloadIdentity) DeepCopy() *GcpWorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(GcpWorkloadIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesAuth) DeepCopyInto(out *KubernetesAuth) {
+	*out = *in
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAuth.
+func (in *KubernetesAuth) DeepCopy() *KubernetesAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesClaim) DeepCopyInto(out *KubernetesClaim) {
+	*out = *in
+	in.KubernetesDestination.DeepCopyInto(&out.KubernetesDestination)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make([]SecretClaimProperty, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClaim.
+func (in *KubernetesClaim) DeepCopy() *KubernetesClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesDestination) DeepCopyInto(out *KubernetesDestination) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesDestination.
+func (in *KubernetesDestination) DeepCopy() *KubernetesDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesProvider) DeepCopyInto(out *KubernetesProvider) {
+	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesProvider.
+func (in *KubernetesProvider) DeepCopy() *KubernetesProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordGenerator) DeepCopyInto(out *PasswordGenerator) {
+	*out = *in
+	if in.NumSymbols != nil {
+		in, out := &in.NumSymbols, &out.NumSymbols
+		*out = new(int)
+		**out = **in
+	}
+	if in.NumDigits != nil {
+		in, out := &in.NumDigits, &out.NumDigits
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordGenerator.
+func (in *PasswordGenerator) DeepCopy() *PasswordGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPolicy) DeepCopyInto(out *PasswordPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPolicy.
+func (in *PasswordPolicy) DeepCopy() *PasswordPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PasswordPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPolicyList) DeepCopyInto(out *PasswordPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PasswordPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPolicyList.
+func (in *PasswordPolicyList) DeepCopy() *PasswordPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PasswordPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPolicySpec) DeepCopyInto(out *PasswordPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPolicySpec.
+func (in *PasswordPolicySpec) DeepCopy() *PasswordPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropertyGenerator) DeepCopyInto(out *PropertyGenerator) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(PasswordGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyGenerator.
+func (in *PropertyGenerator) DeepCopy() *PropertyGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(PropertyGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropertySource) DeepCopyInto(out *PropertySource) {
+	*out = *in
+	if in.PropertyGenerator != nil {
+		in, out := &in.PropertyGenerator, &out.PropertyGenerator
+		*out = new(PropertyGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(RemoteProperty)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertySource.
+func (in *PropertySource) DeepCopy() *PropertySource {
+	if in == nil {
+		return nil
+	}
+	out := new(PropertySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Provider) DeepCopyInto(out *Provider) {
+	*out = *in
+	if in.AzureKeyVault != nil {
+		in, out := &in.AzureKeyVault, &out.AzureKeyVault
+		*out = new(AzureKeyVaultProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GcpSecretsManager != nil {
+		in, out := &in.GcpSecretsManager, &out.GcpSecretsManager
+		*out = new(GcpSecretsManagerProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AwsSecretsManager != nil {
+		in, out := &in.AwsSecretsManager, &out.AwsSecretsManager
+		*out = new(AwsSecretsManagerProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AwsParameterStore != nil {
+		in, out := &in.AwsParameterStore, &out.AwsParameterStore
+		*out = new(AwsParameterStoreProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sops != nil {
+		in, out := &in.Sops, &out.Sops
+		*out = new(SopsProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fake != nil {
+		in, out := &in.Fake, &out.Fake
+		*out = new(FakeProvider)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
+func (in *Provider) DeepCopy() *Provider {
+	if in == nil {
+		return nil
+	}
+	out := new(Provider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteProperty) DeepCopyInto(out *RemoteProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteProperty.
+func (in *RemoteProperty) DeepCopy() *RemoteProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaim) DeepCopyInto(out *SecretClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaim.
+func (in *SecretClaim) DeepCopy() *SecretClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaimDestination) DeepCopyInto(out *SecretClaimDestination) {
+	*out = *in
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretStore != nil {
+		in, out := &in.SecretStore, &out.SecretStore
+		*out = new(StoreDestination)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimDestination.
+func (in *SecretClaimDestination) DeepCopy() *SecretClaimDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaimDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaimList) DeepCopyInto(out *SecretClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimList.
+func (in *SecretClaimList) DeepCopy() *SecretClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaimProperty) DeepCopyInto(out *SecretClaimProperty) {
+	*out = *in
+	in.PropertySource.DeepCopyInto(&out.PropertySource)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimProperty.
+func (in *SecretClaimProperty) DeepCopy() *SecretClaimProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaimProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaimSpec) DeepCopyInto(out *SecretClaimSpec) {
+	*out = *in
+	if in.SecretStoreRef != nil {
+		in, out := &in.SecretStoreRef, &out.SecretStoreRef
+		*out = new(SecretStoreRef)
+		**out = **in
+	}
+	if in.KubernetesClaim != nil {
+		in, out := &in.KubernetesClaim, &out.KubernetesClaim
+		*out = new(KubernetesClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make([]SecretClaimProperty, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]SecretClaimDestination, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimSpec.
+func (in *SecretClaimSpec) DeepCopy() *SecretClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClaimStatus) DeepCopyInto(out *SecretClaimStatus) {
+	*out = *in
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]DestinationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClaimStatus.
+func (in *SecretClaimStatus) DeepCopy() *SecretClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGrant) DeepCopyInto(out *SecretGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGrant.
+func (in *SecretGrant) DeepCopy() *SecretGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGrantFrom) DeepCopyInto(out *SecretGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGrantFrom.
+func (in *SecretGrantFrom) DeepCopy() *SecretGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGrantList) DeepCopyInto(out *SecretGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGrantList.
+func (in *SecretGrantList) DeepCopy() *SecretGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGrantSpec) DeepCopyInto(out *SecretGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]SecretGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]SecretGrantTo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGrantSpec.
+func (in *SecretGrantSpec) DeepCopy() *SecretGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretGrantTo) DeepCopyInto(out *SecretGrantTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretGrantTo.
+func (in *SecretGrantTo) DeepCopy() *SecretGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRef) DeepCopyInto(out *SecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRef.
+func (in *SecretRef) DeepCopy() *SecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStore) DeepCopyInto(out *SecretStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStore.
+func (in *SecretStore) DeepCopy() *SecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreList) DeepCopyInto(out *SecretStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreList.
+func (in *SecretStoreList) DeepCopy() *SecretStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRef.
+func (in *SecretStoreRef) DeepCopy() *SecretStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
+	*out = *in
+	in.Provider.DeepCopyInto(&out.Provider)
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(StoreDeployment)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreSpec.
+func (in *SecretStoreSpec) DeepCopy() *SecretStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreStatus) DeepCopyInto(out *SecretStoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreStatus.
+func (in *SecretStoreStatus) DeepCopy() *SecretStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsConfigMap) DeepCopyInto(out *SopsConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsConfigMap.
+func (in *SopsConfigMap) DeepCopy() *SopsConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsKeys) DeepCopyInto(out *SopsKeys) {
+	*out = *in
+	if in.Age != nil {
+		in, out := &in.Age, &out.Age
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pgp != nil {
+		in, out := &in.Pgp, &out.Pgp
+		*out = new(ValueOrSecretKey)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsKeys.
+func (in *SopsKeys) DeepCopy() *SopsKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsProvider) DeepCopyInto(out *SopsProvider) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(SopsConfigMap)
+		**out = **in
+	}
+	in.Keys.DeepCopyInto(&out.Keys)
+	if in.AgeRecipients != nil {
+		in, out := &in.AgeRecipients, &out.AgeRecipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsProvider.
+func (in *SopsProvider) DeepCopy() *SopsProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoreDeployment) DeepCopyInto(out *StoreDeployment) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreDeployment.
+func (in *StoreDeployment) DeepCopy() *StoreDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(StoreDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoreDestination) DeepCopyInto(out *StoreDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreDestination.
+func (in *StoreDestination) DeepCopy() *StoreDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(StoreDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueOrSecretKey) DeepCopyInto(out *ValueOrSecretKey) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueOrSecretKey.
+func (in *ValueOrSecretKey) DeepCopy() *ValueOrSecretKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueOrSecretKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAppRoleAuth) DeepCopyInto(out *VaultAppRoleAuth) {
+	*out = *in
+	in.RoleId.DeepCopyInto(&out.RoleId)
+	in.SecretId.DeepCopyInto(&out.SecretId)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAppRoleAuth.
+func (in *VaultAppRoleAuth) DeepCopy() *VaultAppRoleAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAppRoleAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuth) DeepCopyInto(out *VaultAuth) {
+	*out = *in
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(VaultTokenAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppRole != nil {
+		in, out := &in.AppRole, &out.AppRole
+		*out = new(VaultAppRoleAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(VaultKubernetesAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuth.
+func (in *VaultAuth) DeepCopy() *VaultAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultKubernetesAuth) DeepCopyInto(out *VaultKubernetesAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultKubernetesAuth.
+func (in *VaultKubernetesAuth) DeepCopy() *VaultKubernetesAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultKubernetesAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultProvider) DeepCopyInto(out *VaultProvider) {
+	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultProvider.
+func (in *VaultProvider) DeepCopy() *VaultProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultTokenAuth) DeepCopyInto(out *VaultTokenAuth) {
+	*out = *in
+	in.Token.DeepCopyInto(&out.Token)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultTokenAuth.
+func (in *VaultTokenAuth) DeepCopy() *VaultTokenAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultTokenAuth)
 	in.DeepCopyInto(out)
 	return out
 }
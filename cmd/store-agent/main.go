@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/all"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+)
+
+var setupLog = ctrl.Log.WithName("setup")
+
+func main() {
+	var listenAddr string
+	var healthAddr string
+	var tlsDir string
+	tracingOpts := tracing.Options{ServiceName: "secret-operator-store-agent"}
+	tracingOpts.BindFlags(flag.CommandLine)
+	flag.StringVar(&listenAddr, "listen-addr", fmt.Sprintf(":%d", storeagent.Port), "The address the store agent API binds to.")
+	flag.StringVar(&healthAddr, "health-addr", fmt.Sprintf(":%d", storeagent.HealthPort), "The address the health endpoint binds to.")
+	flag.StringVar(&tlsDir, "tls-dir", storeagent.TLSDir, "The directory holding tls.crt, tls.key and the client CA bundle ca.crt.")
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New())
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
+	var provider secretoperatorv1alpha1.Provider
+	if err := json.Unmarshal([]byte(os.Getenv(storeagent.ProviderEnv)), &provider); err != nil {
+		setupLog.Error(err, "unable to read provider configuration", "env", storeagent.ProviderEnv)
+		os.Exit(1)
+	}
+	client, err := providers.NewClient(ctx, &provider, storeagent.EnvResolver)
+	if err != nil {
+		setupLog.Error(err, "unable to create provider client")
+		os.Exit(1)
+	}
+
+	certs, err := certificates.NewFileReloader(tlsDir)
+	if err != nil {
+		setupLog.Error(err, "unable to load serving certificate", "dir", tlsDir)
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:      listenAddr,
+		Handler:   storeagent.NewServer(client, ctrl.Log.WithName("store-agent")),
+		TLSConfig: certs.ServerTLSConfig(),
+	}
+	healthServer := &http.Server{
+		Addr:    healthAddr,
+		Handler: storeagent.NewHealthServer(),
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = healthServer.Shutdown(shutdownCtx)
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			setupLog.Error(err, "problem running health endpoint")
+			os.Exit(1)
+		}
+	}()
+
+	setupLog.Info("starting store agent", "addr", listenAddr, "apiVersion", storeagent.APIVersion)
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		setupLog.Error(err, "problem running store agent")
+		os.Exit(1)
+	}
+}
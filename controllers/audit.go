@@ -0,0 +1,71 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/audit"
+)
+
+// generationEvents returns the audit events of generated properties, rotated if they replace earlier values
+func generationEvents(actor audit.Actor, generated []secretoperatorv1alpha1.SecretClaimProperty, values map[string][]byte, rotated bool) []audit.Event {
+	action := audit.ActionGenerate
+	if rotated {
+		action = audit.ActionRotate
+	}
+	events := make([]audit.Event, 0, len(generated))
+	for _, property := range generated {
+		events = append(events, audit.Event{Actor: actor, Action: action, Property: property.Name, Value: values[property.Name]})
+	}
+	return events
+}
+
+// readEvents returns the audit events of the properties read from the claim's secret store
+func readEvents(actor audit.Actor, claim secretoperatorv1alpha1.SecretClaim, values map[string][]byte) []audit.Event {
+	var events []audit.Event
+	for _, property := range claim.ClaimProperties() {
+		if property.PropertySource.Remote == nil {
+			continue
+		}
+		ref := claim.Spec.SecretStoreRef
+		events = append(events, audit.Event{
+			Actor:       actor,
+			Action:      audit.ActionRead,
+			Destination: fmt.Sprintf("secretstore:%s/%s", ref.Name, property.PropertySource.Remote.Key),
+			Property:    property.Name,
+			Value:       values[property.Name],
+		})
+	}
+	return events
+}
+
+// writeEvents returns the audit events of writing every value to destination
+func writeEvents(actor audit.Actor, destination string, values map[string][]byte) []audit.Event {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	events := make([]audit.Event, 0, len(names))
+	for _, name := range names {
+		events = append(events, audit.Event{Actor: actor, Action: audit.ActionWrite, Destination: destination, Property: name, Value: values[name]})
+	}
+	return events
+}
@@ -0,0 +1,183 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/secrets-operator/secrets-operator/pkg/audit"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/clusterclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/generation"
+	"github.com/secrets-operator/secrets-operator/pkg/metrics"
+	"github.com/secrets-operator/secrets-operator/pkg/ownership"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const clusterSecretClaimKind = "ClusterSecretClaim"
+
+// ClusterSecretClaimReconciler reconciles a ClusterSecretClaim object
+type ClusterSecretClaimReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Audit records the values generated and written for claims. It is nil if auditing is disabled.
+	Audit *audit.Logger
+}
+
+// +kubebuilder:rbac:groups=secret-operator.io,resources=clustersecretclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=secret-operator.io,resources=clustersecretclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+
+func (r *ClusterSecretClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClusterSecretClaim.Reconcile",
+		attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+	log := tracing.Logger(ctx, r.Log.WithValues("clustersecretclaim", req.NamespacedName))
+
+	var claim secretoperatorv1alpha1.ClusterSecretClaim
+	if err := r.Get(ctx, req.NamespacedName, &claim); err != nil {
+		log.Error(err, "unable to fetch ClusterSecretClaim")
+		if apierrors.IsNotFound(err) {
+			metrics.ForgetClaim(clusterSecretClaimKind, "", req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	handler := clusterclaim.NewHandler(claim, ctx, r.Client)
+	if !claim.DeletionTimestamp.IsZero() {
+		if !ctrlutil.ContainsFinalizer(&claim, ownership.Finalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := handler.Cleanup(); err != nil {
+			log.Error(err, "unable to clean up secrets of claim")
+			return ctrl.Result{}, err
+		}
+		ctrlutil.RemoveFinalizer(&claim, ownership.Finalizer)
+		return ctrl.Result{}, r.Update(ctx, &claim)
+	}
+	if !ctrlutil.ContainsFinalizer(&claim, ownership.Finalizer) {
+		ctrlutil.AddFinalizer(&claim, ownership.Finalizer)
+		if err := r.Update(ctx, &claim); err != nil {
+			log.Error(err, "unable to add finalizer to claim")
+			return ctrl.Result{}, err
+		}
+	}
+	if failedTerminally(claim.Status.Conditions, claim.Generation) {
+		log.V(1).Info("skipping claim that cannot be synced until its spec changes")
+		return ctrl.Result{}, nil
+	}
+
+	handleErr := handler.Handle()
+	if handleErr != nil {
+		log.Error(handleErr, "handler failure")
+		metrics.ClaimSyncError(clusterSecretClaimKind, "", claim.Name, metrics.ReasonDestination)
+	}
+	r.recordRotations(claim, handler)
+	if err := r.Audit.Record(ctx, r.auditEvents(claim, handler)...); err != nil {
+		log.Error(err, "unable to record audit entries")
+	}
+
+	status := *claim.Status.DeepCopy()
+	status.Namespaces = handler.Namespaces()
+	setReadyCondition(&status.Conditions, claim.Generation, handleErr)
+	if !reflect.DeepEqual(claim.Status, status) {
+		claim.Status = status
+		if err := r.Status().Update(ctx, &claim); err != nil {
+			log.Error(err, "unable to update claim status")
+			metrics.ClaimSyncError(clusterSecretClaimKind, "", claim.Name, metrics.ReasonStatus)
+			return ctrl.Result{}, err
+		}
+	}
+	return resultFor(handleErr)
+}
+
+// recordRotations updates the metrics of the properties generated by handler. Values kept
+// from an existing replica are dated by its creation, so their age is known after a restart.
+func (r *ClusterSecretClaimReconciler) recordRotations(claim secretoperatorv1alpha1.ClusterSecretClaim, handler *clusterclaim.Handler) {
+	if len(handler.Namespaces()) == 0 {
+		return
+	}
+	now := time.Now()
+	generated := map[string]bool{}
+	for _, property := range handler.Generated() {
+		generated[property.Name] = true
+		// A value is only generated for a property missing from the replicas, it replaces none.
+		metrics.RecordGenerated(generation.GeneratorType(*property.PropertySource.PropertyGenerator), false)
+		metrics.SetRotated(clusterSecretClaimKind, "", claim.Name, property.Name, now)
+	}
+	if handler.ValuesTime().IsZero() {
+		return
+	}
+	for _, property := range claim.Spec.Template.Properties {
+		if property.PropertySource.PropertyGenerator != nil && !generated[property.Name] {
+			metrics.SetRotatedIfUnknown(clusterSecretClaimKind, "", claim.Name, property.Name, handler.ValuesTime())
+		}
+	}
+}
+
+// auditEvents returns the audit events of the values generated and written by handler
+func (r *ClusterSecretClaimReconciler) auditEvents(claim secretoperatorv1alpha1.ClusterSecretClaim, handler *clusterclaim.Handler) []audit.Event {
+	actor := audit.Actor{Kind: clusterSecretClaimKind, Name: claim.Name}
+	events := generationEvents(actor, handler.Generated(), handler.Values(), false)
+	for _, namespace := range handler.Written() {
+		destination := fmt.Sprintf("kubernetes:%s/%s", namespace, claim.Spec.Template.Name)
+		events = append(events, writeEvents(actor, destination, handler.Values())...)
+	}
+	return events
+}
+
+// claimsForNamespace enqueues every ClusterSecretClaim when a namespace changes, so replicas
+// follow namespaces that start or stop matching a selector.
+func (r *ClusterSecretClaimReconciler) claimsForNamespace(object client.Object) []reconcile.Request {
+	var claims secretoperatorv1alpha1.ClusterSecretClaimList
+	if err := r.List(context.Background(), &claims); err != nil {
+		r.Log.Error(err, "unable to list cluster secret claims", "namespace", object.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(claims.Items))
+	for _, claim := range claims.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: claim.Name}})
+	}
+	return requests
+}
+
+func (r *ClusterSecretClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&secretoperatorv1alpha1.ClusterSecretClaim{}).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.claimsForNamespace)).
+		WithOptions(controller.Options{RateLimiter: retry.NewRateLimiter()}).
+		Complete(r)
+}
@@ -0,0 +1,138 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ClusterSecretStoreReconciler reconciles a ClusterSecretStore object
+type ClusterSecretStoreReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// OperatorNamespace is the namespace the shared store deployments run in
+	OperatorNamespace string
+	// StoreImage is the image of store deployments that do not override it
+	StoreImage string
+	// Authority issues the serving certificates of store agents
+	Authority *certificates.Authority
+	// DefaultMode applies to stores that do not set spec.mode
+	DefaultMode secretoperatorv1alpha1.StoreMode
+	// Recorder records the events of stores
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=secret-operator.io,resources=clustersecretstores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=secret-operator.io,resources=clustersecretstores/status,verbs=get;update;patch
+
+func (r *ClusterSecretStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClusterSecretStore.Reconcile",
+		attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+	log := tracing.Logger(ctx, r.Log.WithValues("clustersecretstore", req.NamespacedName))
+
+	var store secretoperatorv1alpha1.ClusterSecretStore
+	if err := r.Get(ctx, req.NamespacedName, &store); err != nil {
+		log.Error(err, "unable to fetch ClusterSecretStore")
+		if apierrors.IsNotFound(err) {
+			deleted := &secretoperatorv1alpha1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: req.Name}}
+			forgetStoreMetrics(deleted, r.OperatorNamespace)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// A cluster store is backed by a single store deployment in the operator namespace,
+	// shared by every namespace its namespaceSelector admits.
+	if err := validateCredentials(ctx, r.Client, &store); err != nil {
+		log.Error(err, "invalid store credentials")
+		if err := updateStoreStatus(ctx, r.Client, r.Recorder, &store, failureReason(err), err); err != nil {
+			log.Error(err, "unable to update store status")
+		}
+		return resultFor(err)
+	}
+
+	if store.Spec.ModeOr(r.DefaultMode) == secretoperatorv1alpha1.StoreModeInProcess {
+		if err := removeStoreDeployment(ctx, r.Client, &store, r.OperatorNamespace); err != nil {
+			log.Error(err, "unable to remove store deployment")
+			r.Recorder.Eventf(&store, corev1.EventTypeWarning, EventSyncFailed, "Unable to remove store deployment: %v", err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, updateStoreStatus(ctx, r.Client, r.Recorder, &store, "", nil)
+	}
+
+	renewal, err := reconcileStoreDeployment(ctx, r.Client, r.Scheme, r.Authority, &store, r.OperatorNamespace, r.StoreImage)
+	if err != nil {
+		log.Error(err, "unable to reconcile store deployment")
+		if err := updateStoreStatus(ctx, r.Client, r.Recorder, &store, failureReason(err), err); err != nil {
+			log.Error(err, "unable to update store status")
+		}
+		return resultFor(err)
+	}
+
+	return renewalResult(renewal), updateStoreStatus(ctx, r.Client, r.Recorder, &store, "", nil)
+}
+
+// storesForSecret enqueues the cluster stores that reference a secret, so their store agents
+// restart with the new credentials.
+func (r *ClusterSecretStoreReconciler) storesForSecret(object client.Object) []reconcile.Request {
+	var stores secretoperatorv1alpha1.ClusterSecretStoreList
+	if err := r.List(context.Background(), &stores); err != nil {
+		r.Log.Error(err, "unable to list cluster secret stores")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range stores.Items {
+		if referencesSecret(&stores.Items[i], object) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: stores.Items[i].Name}})
+		}
+	}
+	return requests
+}
+
+func (r *ClusterSecretStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&secretoperatorv1alpha1.ClusterSecretStore{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.storesForSecret)).
+		WithOptions(controller.Options{RateLimiter: retry.NewRateLimiter()}).
+		Complete(r)
+}
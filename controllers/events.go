@@ -0,0 +1,70 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/clusterclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+)
+
+// Reasons of the events recorded on claims and stores. Events name properties, never their values.
+const (
+	EventCreated           = "Created"
+	EventUpdated           = "Updated"
+	EventRotated           = "Rotated"
+	EventAdopted           = "Adopted"
+	EventOwnershipConflict = "OwnershipConflict"
+	EventPolicyViolation   = "PolicyViolation"
+	EventProviderError     = "ProviderError"
+	EventValidationFailed  = "ValidationFailed"
+	EventSyncFailed        = "SyncFailed"
+	EventReady             = "Ready"
+)
+
+// failureReason returns the event reason of an error syncing a claim
+func failureReason(err error) string {
+	switch {
+	case retry.IsTerminal(err):
+		return EventValidationFailed
+	case errors.Is(err, kubernetesclaim.ErrOwnershipConflict), errors.Is(err, controllerutil.ErrOwnershipConflict):
+		return EventOwnershipConflict
+	case errors.Is(err, clusterclaim.ErrPolicyViolation):
+		return EventPolicyViolation
+	case errors.Is(err, providers.ErrProvider):
+		return EventProviderError
+	default:
+		return EventSyncFailed
+	}
+}
+
+// propertyNames returns the sorted, comma separated names of the properties
+func propertyNames(properties []secretoperatorv1alpha1.SecretClaimProperty) string {
+	names := make([]string, 0, len(properties))
+	for _, property := range properties {
+		names = append(names, property.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
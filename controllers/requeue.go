@@ -0,0 +1,147 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/clusterclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ownershipConflictInterval is how often a claim is retried while a Secret it would write is owned
+// by someone else. Such Secrets are not watched, so their removal does not trigger a reconcile.
+// Namespaces skipped for a PasswordPolicy violation are retried as often, policies are not watched either.
+const ownershipConflictInterval = 5 * time.Minute
+
+// minRenewalInterval is the shortest delay before a store is reconciled again to renew its serving
+// certificate, for renewals that are already due, e.g. after a CA rotation or with clock skew
+const minRenewalInterval = 30 * time.Second
+
+// reasonSynced is the reason of a Ready condition that is True
+const reasonSynced = "Synced"
+
+// resultFor returns the result of a reconcile that failed with errs. Terminal errors are not
+// retried, throttled requests are retried after the delay the store asked for and ownership
+// conflicts and policy violations after ownershipConflictInterval. Other errors are returned, so the work queue
+// retries them with backoff.
+func resultFor(errs ...error) (ctrl.Result, error) {
+	var transient []error
+	var requeueAfter time.Duration
+	conflict := false
+	for _, err := range flatten(errs) {
+		delay, throttled := providers.RetryAfter(err)
+		switch {
+		case retry.IsTerminal(err):
+		case errors.Is(err, kubernetesclaim.ErrOwnershipConflict), errors.Is(err, controllerutil.ErrOwnershipConflict),
+			errors.Is(err, clusterclaim.ErrPolicyViolation):
+			conflict = true
+		case throttled:
+			if delay > requeueAfter {
+				requeueAfter = delay
+			}
+		default:
+			transient = append(transient, err)
+		}
+	}
+	if len(transient) > 0 {
+		return ctrl.Result{}, utilerrors.NewAggregate(transient)
+	}
+	if conflict && (requeueAfter == 0 || ownershipConflictInterval < requeueAfter) {
+		requeueAfter = ownershipConflictInterval
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// renewalResult returns the result of a store reconcile that is due again at renewal
+func renewalResult(renewal time.Time) ctrl.Result {
+	requeueAfter := time.Until(renewal)
+	if requeueAfter < minRenewalInterval {
+		requeueAfter = minRenewalInterval
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
+// setReadyCondition sets the Ready condition of a claim at generation after a reconcile that
+// failed with errs. The reason is ValidationFailed only if every error is terminal.
+func setReadyCondition(conditions *[]metav1.Condition, generation int64, errs ...error) {
+	condition := metav1.Condition{
+		Type:               secretoperatorv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reasonSynced,
+	}
+	if flat := flatten(errs); len(flat) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = EventValidationFailed
+		for _, err := range flat {
+			if !retry.IsTerminal(err) {
+				condition.Reason = failureReason(err)
+				break
+			}
+		}
+		condition.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// failedTerminally reports whether the claim at generation failed with terminal errors only, so
+// it is not reconciled again until its spec changes
+func failedTerminally(conditions []metav1.Condition, generation int64) bool {
+	ready := meta.FindStatusCondition(conditions, secretoperatorv1alpha1.ConditionReady)
+	return ready != nil && ready.Status == metav1.ConditionFalse && ready.Reason == EventValidationFailed &&
+		ready.ObservedGeneration == generation
+}
+
+// flatten returns the errors of every Aggregate in errs, recursively, without nil errors. An
+// Aggregate marked terminal as a whole is kept, so its errors stay terminal.
+func flatten(errs []error) []error {
+	var flat []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if aggregate := aggregateOf(err); aggregate != nil {
+			flat = append(flat, flatten(aggregate.Errors())...)
+		} else {
+			flat = append(flat, err)
+		}
+	}
+	return flat
+}
+
+// aggregateOf returns the Aggregate err wraps, unless it was marked terminal when wrapping it
+func aggregateOf(err error) utilerrors.Aggregate {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if aggregate, ok := err.(utilerrors.Aggregate); ok {
+			return aggregate
+		}
+		if marker, ok := err.(interface{ Is(error) bool }); ok && marker.Is(retry.ErrTerminal) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -18,53 +18,330 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/audit"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers"
 	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/factory"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/credentials"
+	"github.com/secrets-operator/secrets-operator/pkg/deployment"
+	"github.com/secrets-operator/secrets-operator/pkg/generation"
+	"github.com/secrets-operator/secrets-operator/pkg/metrics"
+	"github.com/secrets-operator/secrets-operator/pkg/ownership"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/secretstores"
+	"github.com/secrets-operator/secrets-operator/pkg/source"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
+const secretClaimKind = "SecretClaim"
+
 // SecretClaimReconciler reconciles a SecretClaim object
 type SecretClaimReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// OperatorNamespace is the namespace the store agents of ClusterSecretStores run in
+	OperatorNamespace string
+	// Authority provides the client certificate used to reach store agents
+	Authority *certificates.Authority
+	// DefaultStoreMode applies to stores that do not set spec.mode
+	DefaultStoreMode secretoperatorv1alpha1.StoreMode
+	// Recorder records the events of claims
+	Recorder record.EventRecorder
+	// Audit records the values generated, read and written for claims. It is nil if auditing is disabled.
+	Audit *audit.Logger
 }
 
 // +kubebuilder:rbac:groups=secret-operator.io,resources=secretclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=secret-operator.io,resources=secretclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=secret-operator.io,resources=secretgrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-func (r *SecretClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithValues("secretclaim", req.NamespacedName)
+func (r *SecretClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "SecretClaim.Reconcile",
+		attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+	log := tracing.Logger(ctx, r.Log.WithValues("secretclaim", req.NamespacedName))
 
 	var claim secretoperatorv1alpha1.SecretClaim
 	if err := r.Get(ctx, req.NamespacedName, &claim); err != nil {
-		log.Error(err, "unable to fetch CronJob")
+		log.Error(err, "unable to fetch SecretClaim")
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
+		if apierrors.IsNotFound(err) {
+			metrics.ForgetClaim(secretClaimKind, req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	handler, err := factory.CreateClaimHandler(claim, ctx)
+	if !claim.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, log, claim)
+	}
+	if !ctrlutil.ContainsFinalizer(&claim, ownership.Finalizer) {
+		ctrlutil.AddFinalizer(&claim, ownership.Finalizer)
+		if err := r.Update(ctx, &claim); err != nil {
+			log.Error(err, "unable to add finalizer to claim")
+			return ctrl.Result{}, err
+		}
+	}
+	if failedTerminally(claim.Status.Conditions, claim.Generation) {
+		log.V(1).Info("skipping claim that cannot be synced until its spec changes")
+		return ctrl.Result{}, nil
+	}
+
+	storeClient, err := r.storeClient(ctx, claim)
+	if err != nil {
+		log.Error(err, "unable to resolve secret store for claim")
+		r.Recorder.Eventf(&claim, corev1.EventTypeWarning, EventProviderError, "Unable to reach secret store: %v", err)
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonStore)
+		return r.fail(ctx, log, &claim, err)
+	}
+
+	handlers, err := factory.CreateClaimHandlers(claim, ctx, r.Client, storeClient)
 	if err != nil {
 		log.Error(err, "unable to create handler for claim")
-		return ctrl.Result{Requeue: true, RequeueAfter: 30}, err
+		r.Recorder.Eventf(&claim, corev1.EventTypeWarning, EventValidationFailed, "Invalid destinations: %v", err)
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonInvalid)
+		return r.fail(ctx, log, &claim, err)
+	}
+
+	// Properties are resolved once and the same values are written to every destination.
+	// Generated values already held by a destination are kept.
+	existing, existingTime, err := existingValues(handlers)
+	if err != nil {
+		log.Error(err, "unable to read current values of claim")
+		r.Recorder.Eventf(&claim, corev1.EventTypeWarning, failureReason(err), "Unable to read current values: %v", err)
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonDestination)
+		return r.fail(ctx, log, &claim, err)
 	}
-	err = handler.Handle()
+	values, err := source.HandleProperties(ctx, claim.ClaimProperties(), existing, storeClient)
 	if err != nil {
-		log.Error(err, "handler failure")
-		return ctrl.Result{Requeue: true, RequeueAfter: 30}, err
+		log.Error(err, "unable to source claim properties")
+		r.Recorder.Eventf(&claim, corev1.EventTypeWarning, failureReason(err), "Unable to source properties: %v", err)
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonSource)
+		return r.fail(ctx, log, &claim, err)
+	}
+	generated := source.GeneratedProperties(claim.ClaimProperties(), existing)
+	for _, property := range generated {
+		// A value is only generated for a property missing from every destination, it replaces none.
+		metrics.RecordGenerated(generation.GeneratorType(*property.PropertySource.PropertyGenerator), false)
+	}
+	actor := audit.Actor{Kind: secretClaimKind, Namespace: claim.Namespace, Name: claim.Name}
+	auditEvents := append(generationEvents(actor, generated, values, false), readEvents(actor, claim, values)...)
+
+	var errs []error
+	synced := false
+	statuses := make([]secretoperatorv1alpha1.DestinationStatus, 0, len(handlers))
+	for _, handler := range handlers {
+		status := secretoperatorv1alpha1.DestinationStatus{Destination: handler.Destination()}
+		result, err := handler.Handle(values)
+		if err != nil {
+			log.Error(err, "handler failure", "destination", handler.Destination())
+			r.Recorder.Eventf(&claim, corev1.EventTypeWarning, failureReason(err), "Unable to write %s: %v", handler.Destination(), err)
+			metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonDestination)
+			errs = append(errs, fmt.Errorf("%s: %w", handler.Destination(), err))
+			status.Message = err.Error()
+		} else {
+			if result != claimhandlers.ResultUnchanged {
+				r.Recorder.Eventf(&claim, corev1.EventTypeNormal, string(result), "Wrote properties %s to %s",
+					propertyNames(claim.ClaimProperties()), handler.Destination())
+				auditEvents = append(auditEvents, writeEvents(actor, handler.Destination(), values)...)
+				now := metav1.Now()
+				status.LastSyncTime = &now
+			}
+			synced = true
+			status.Synced = true
+		}
+		status.LastSyncTime = lastSyncTime(claim.Status.Destinations, status)
+		statuses = append(statuses, status)
 	}
 
+	if synced {
+		now := time.Now()
+		for _, property := range generated {
+			metrics.SetRotated(secretClaimKind, claim.Namespace, claim.Name, property.Name, now)
+		}
+	}
+	// Values kept from an existing destination are dated by its creation, so their age is known after a restart
+	if !existingTime.IsZero() {
+		isGenerated := map[string]bool{}
+		for _, property := range generated {
+			isGenerated[property.Name] = true
+		}
+		for _, property := range claim.ClaimProperties() {
+			if property.PropertySource.PropertyGenerator != nil && !isGenerated[property.Name] {
+				metrics.SetRotatedIfUnknown(secretClaimKind, claim.Namespace, claim.Name, property.Name, existingTime)
+			}
+		}
+	}
+
+	if err := r.Audit.Record(ctx, auditEvents...); err != nil {
+		log.Error(err, "unable to record audit entries")
+	}
+
+	if err := kubernetesclaim.PruneSecrets(ctx, r.Client, &claim, kubernetesclaim.Targets(claim)); err != nil {
+		log.Error(err, "unable to prune secrets of removed destinations")
+		r.Recorder.Eventf(&claim, corev1.EventTypeWarning, EventSyncFailed, "Unable to delete secrets of removed destinations: %v", err)
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonPrune)
+		errs = append(errs, err)
+	}
+
+	status := *claim.Status.DeepCopy()
+	status.Destinations = statuses
+	setReadyCondition(&status.Conditions, claim.Generation, errs...)
+	if reflect.DeepEqual(status, claim.Status) {
+		return resultFor(errs...)
+	}
+	claim.Status = status
+	if err := r.Status().Update(ctx, &claim); err != nil {
+		log.Error(err, "unable to update claim status")
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonStatus)
+		return ctrl.Result{}, err
+	}
+	return resultFor(errs...)
+}
+
+// fail records err, which stopped the claim from syncing, in its Ready condition and returns the
+// result retrying the claim as err requires
+func (r *SecretClaimReconciler) fail(ctx context.Context, log logr.Logger, claim *secretoperatorv1alpha1.SecretClaim, err error) (ctrl.Result, error) {
+	setReadyCondition(&claim.Status.Conditions, claim.Generation, err)
+	if statusErr := r.Status().Update(ctx, claim); statusErr != nil {
+		log.Error(statusErr, "unable to update claim status")
+		metrics.ClaimSyncError(secretClaimKind, claim.Namespace, claim.Name, metrics.ReasonStatus)
+		return ctrl.Result{}, statusErr
+	}
+	return resultFor(err)
+}
+
+// storeClient returns a client for the claim's secret store, or nil if the claim has none. Stores in
+// InProcess mode are called directly, all others through their store agent.
+func (r *SecretClaimReconciler) storeClient(ctx context.Context, claim secretoperatorv1alpha1.SecretClaim) (providers.Client, error) {
+	if claim.Spec.SecretStoreRef == nil {
+		return nil, nil
+	}
+	store, err := secretstores.Get(ctx, r.Client, *claim.Spec.SecretStoreRef, claim.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced, ok := store.(*secretoperatorv1alpha1.SecretStore); ok {
+		if err := validateNamespacedStore(namespaced, r.DefaultStoreMode); err != nil {
+			return nil, err
+		}
+	}
+	if store.GetSpec().ModeOr(r.DefaultStoreMode) == secretoperatorv1alpha1.StoreModeInProcess {
+		storeClient, err := providers.NewClient(ctx, &store.GetSpec().Provider, credentials.SecretResolver(r.Client))
+		if err != nil {
+			return nil, providers.ProviderError(err)
+		}
+		return metrics.InstrumentClient(tracing.InstrumentClient(storeClient, store), store), nil
+	}
+	httpClient, err := r.Authority.HTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	url := storeagent.URL(deployment.Name(store), deployment.Namespace(store, r.OperatorNamespace))
+	return metrics.InstrumentClient(tracing.InstrumentClient(storeagent.NewClient(url, httpClient), store), store), nil
+}
+
+// lastSyncTime keeps the time of the previous sync of a destination that was not written this
+// time, because it already held the values or failed.
+func lastSyncTime(previous []secretoperatorv1alpha1.DestinationStatus, status secretoperatorv1alpha1.DestinationStatus) *metav1.Time {
+	if status.LastSyncTime != nil {
+		return status.LastSyncTime
+	}
+	for _, p := range previous {
+		if p.Destination == status.Destination && p.LastSyncTime != nil {
+			return p.LastSyncTime
+		}
+	}
+	if status.Synced {
+		now := metav1.Now()
+		return &now
+	}
+	return nil
+}
+
+// existingValues returns the values held by the first destination written before, or nil, and
+// when that destination was created if it knows
+func existingValues(handlers []claimhandlers.ClaimHandler) (map[string][]byte, time.Time, error) {
+	for _, handler := range handlers {
+		values, err := handler.Values()
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("%s: %w", handler.Destination(), err)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		var created time.Time
+		if timer, ok := handler.(claimhandlers.CreationTimer); ok {
+			if created, err = timer.CreationTime(); err != nil {
+				return nil, time.Time{}, fmt.Errorf("%s: %w", handler.Destination(), err)
+			}
+		}
+		return values, created, nil
+	}
+	return nil, time.Time{}, nil
+}
+
+// finalize removes the Secrets written for a claim that is being deleted and releases the finalizer.
+func (r *SecretClaimReconciler) finalize(ctx context.Context, log logr.Logger, claim secretoperatorv1alpha1.SecretClaim) (ctrl.Result, error) {
+	if !ctrlutil.ContainsFinalizer(&claim, ownership.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	storeClient, err := r.storeClient(ctx, claim)
+	if err != nil {
+		log.Error(err, "unable to resolve secret store for claim, its store secrets are left in place")
+	}
+	handlers, err := factory.CreateClaimHandlers(claim, ctx, r.Client, storeClient)
+	if err == nil {
+		for _, handler := range handlers {
+			if err := handler.Cleanup(); err != nil {
+				log.Error(err, "unable to clean up destination of claim", "destination", handler.Destination())
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	if err := kubernetesclaim.PruneSecrets(ctx, r.Client, &claim, nil); err != nil {
+		log.Error(err, "unable to clean up secrets of claim")
+		return ctrl.Result{}, err
+	}
+
+	ctrlutil.RemoveFinalizer(&claim, ownership.Finalizer)
+	if err := r.Update(ctx, &claim); err != nil {
+		log.Error(err, "unable to remove finalizer from claim")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
 func (r *SecretClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretoperatorv1alpha1.SecretClaim{}).
+		// Status and finalizer updates do not change the spec, so they do not sync the claim again.
+		For(&secretoperatorv1alpha1.SecretClaim{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{RateLimiter: retry.NewRateLimiter()}).
 		Complete(r)
 }
@@ -0,0 +1,258 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/providers/fake"
+)
+
+var _ = Describe("SecretClaim with a fake store", func() {
+	const namespace = "default"
+	ctx := context.Background()
+
+	It("reads remote properties from the store and writes them back", func() {
+		provider := &secretoperatorv1alpha1.FakeProvider{
+			Data: map[string]string{"db-password": "s3cr3t"},
+		}
+		store := &secretoperatorv1alpha1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake", Namespace: namespace},
+			Spec: secretoperatorv1alpha1.SecretStoreSpec{
+				Provider: secretoperatorv1alpha1.Provider{Fake: provider},
+			},
+		}
+		Expect(k8sClient.Create(ctx, store)).To(Succeed())
+
+		claim := &secretoperatorv1alpha1.SecretClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec: secretoperatorv1alpha1.SecretClaimSpec{
+				SecretStoreRef: &secretoperatorv1alpha1.SecretStoreRef{Name: "fake", Kind: "SecretStore"},
+				Properties: []secretoperatorv1alpha1.SecretClaimProperty{{
+					Name: "password",
+					PropertySource: secretoperatorv1alpha1.PropertySource{
+						Remote: &secretoperatorv1alpha1.RemoteProperty{Key: "db-password"},
+					},
+				}},
+				Destinations: []secretoperatorv1alpha1.SecretClaimDestination{
+					{Kubernetes: &secretoperatorv1alpha1.KubernetesDestination{Name: "db"}},
+					{SecretStore: &secretoperatorv1alpha1.StoreDestination{NamePrefix: "copy-"}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db"}, &secret)
+		}, 10*time.Second).Should(Succeed())
+		Expect(kubernetesclaim.SecretValues(secret)).To(HaveKeyWithValue("password", []byte("s3cr3t")))
+
+		Eventually(func() map[string][]byte {
+			return fake.ClientFor(provider).Secrets()
+		}, 10*time.Second).Should(HaveKeyWithValue("copy-password", []byte("s3cr3t")))
+	})
+
+	It("reports claims that cannot be synced until their spec changes", func() {
+		claim := &secretoperatorv1alpha1.SecretClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-store", Namespace: namespace},
+			Spec: secretoperatorv1alpha1.SecretClaimSpec{
+				Properties: []secretoperatorv1alpha1.SecretClaimProperty{{
+					Name: "password",
+					PropertySource: secretoperatorv1alpha1.PropertySource{
+						Remote: &secretoperatorv1alpha1.RemoteProperty{Key: "db-password"},
+					},
+				}},
+				Destinations: []secretoperatorv1alpha1.SecretClaimDestination{
+					{Kubernetes: &secretoperatorv1alpha1.KubernetesDestination{Name: "no-store"}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+
+		var ready *metav1.Condition
+		Eventually(func() *metav1.Condition {
+			var current secretoperatorv1alpha1.SecretClaim
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "no-store"}, &current); err != nil {
+				return nil
+			}
+			ready = meta.FindStatusCondition(current.Status.Conditions, secretoperatorv1alpha1.ConditionReady)
+			return ready
+		}, 10*time.Second).ShouldNot(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(EventValidationFailed))
+		Expect(ready.ObservedGeneration).To(Equal(claim.Generation))
+	})
+
+	It("keeps generated passwords across reconciles", func() {
+		numDigits, numSymbols := 4, 0
+		claim := &secretoperatorv1alpha1.SecretClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: namespace},
+			Spec: secretoperatorv1alpha1.SecretClaimSpec{
+				KubernetesClaim: &secretoperatorv1alpha1.KubernetesClaim{
+					KubernetesDestination: secretoperatorv1alpha1.KubernetesDestination{Name: "generated"},
+					Properties: []secretoperatorv1alpha1.SecretClaimProperty{{
+						Name: "password",
+						PropertySource: secretoperatorv1alpha1.PropertySource{
+							PropertyGenerator: &secretoperatorv1alpha1.PropertyGenerator{
+								Password: &secretoperatorv1alpha1.PasswordGenerator{
+									Length: 16, NumDigits: &numDigits, NumSymbols: &numSymbols, AllowRepeat: true,
+								},
+							},
+						},
+					}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+
+		key := types.NamespacedName{Namespace: namespace, Name: "generated"}
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}, 10*time.Second).Should(Succeed())
+		password := kubernetesclaim.SecretValues(secret)["password"]
+		Expect(password).To(HaveLen(16))
+
+		// Status and finalizer updates must not write the Secret again
+		version := secret.ResourceVersion
+		Consistently(func() string {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.ResourceVersion
+		}, 3*time.Second).Should(Equal(version))
+
+		// A spec change reconciles the claim again, the Secret gets the label but keeps its password
+		Expect(k8sClient.Get(ctx, key, claim)).To(Succeed())
+		claim.Spec.KubernetesClaim.Labels = map[string]string{"app": "db"}
+		Expect(k8sClient.Update(ctx, claim)).To(Succeed())
+		Eventually(func() map[string]string {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Labels
+		}, 10*time.Second).Should(HaveKeyWithValue("app", "db"))
+		Expect(kubernetesclaim.SecretValues(secret)).To(HaveKeyWithValue("password", password))
+	})
+})
+
+var _ = Describe("SecretClaim writing into another namespace", func() {
+	const (
+		namespace       = "default"
+		targetNamespace = "granted"
+	)
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		target := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: targetNamespace}}
+		if err := k8sClient.Create(ctx, target); !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	newClaim := func(name string) *secretoperatorv1alpha1.SecretClaim {
+		return &secretoperatorv1alpha1.SecretClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: secretoperatorv1alpha1.SecretClaimSpec{
+				KubernetesClaim: &secretoperatorv1alpha1.KubernetesClaim{
+					KubernetesDestination: secretoperatorv1alpha1.KubernetesDestination{Name: name, Namespace: targetNamespace},
+					Properties: []secretoperatorv1alpha1.SecretClaimProperty{{
+						Name: "token",
+						PropertySource: secretoperatorv1alpha1.PropertySource{
+							PropertyGenerator: &secretoperatorv1alpha1.PropertyGenerator{Hmac: true},
+						},
+					}},
+				},
+			},
+		}
+	}
+	newGrant := func(name, secretName string) *secretoperatorv1alpha1.SecretGrant {
+		return &secretoperatorv1alpha1.SecretGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: targetNamespace},
+			Spec: secretoperatorv1alpha1.SecretGrantSpec{
+				From: []secretoperatorv1alpha1.SecretGrantFrom{{Kind: "SecretClaim", Namespace: namespace}},
+				To:   []secretoperatorv1alpha1.SecretGrantTo{{Name: secretName}},
+			},
+		}
+	}
+	readyCondition := func(name string) func() *metav1.Condition {
+		return func() *metav1.Condition {
+			var current secretoperatorv1alpha1.SecretClaim
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &current); err != nil {
+				return nil
+			}
+			return meta.FindStatusCondition(current.Status.Conditions, secretoperatorv1alpha1.ConditionReady)
+		}
+	}
+
+	It("refuses the write without a SecretGrant", func() {
+		// A grant for another Secret does not allow this one
+		Expect(k8sClient.Create(ctx, newGrant("other-secret", "other"))).To(Succeed())
+		Expect(k8sClient.Create(ctx, newClaim("refused"))).To(Succeed())
+
+		Eventually(readyCondition("refused"), 10*time.Second).Should(And(
+			Not(BeNil()),
+			WithTransform(func(c *metav1.Condition) metav1.ConditionStatus { return c.Status }, Equal(metav1.ConditionFalse)),
+			WithTransform(func(c *metav1.Condition) string { return c.Message }, ContainSubstring("no SecretGrant")),
+		))
+		Consistently(func() bool {
+			var secret corev1.Secret
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: targetNamespace, Name: "refused"}, &secret)
+			return apierrors.IsNotFound(err)
+		}, 3*time.Second).Should(BeTrue())
+	})
+
+	It("writes with a SecretGrant and stops writing once it is revoked", func() {
+		grant := newGrant("allow-default", "allowed")
+		Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+		claim := newClaim("allowed")
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+
+		key := types.NamespacedName{Namespace: targetNamespace, Name: "allowed"}
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}, 10*time.Second).Should(Succeed())
+		Expect(kubernetesclaim.SecretValues(secret)).To(HaveKey("token"))
+		Eventually(readyCondition("allowed"), 10*time.Second).Should(WithTransform(
+			func(c *metav1.Condition) metav1.ConditionStatus {
+				if c == nil {
+					return ""
+				}
+				return c.Status
+			}, Equal(metav1.ConditionTrue)))
+
+		// Once the grant is revoked, changes of the claim are no longer written
+		Expect(k8sClient.Delete(ctx, grant)).To(Succeed())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "allowed"}, claim)).To(Succeed())
+		claim.Spec.KubernetesClaim.Labels = map[string]string{"app": "revoked"}
+		Expect(k8sClient.Update(ctx, claim)).To(Succeed())
+
+		Eventually(readyCondition("allowed"), 10*time.Second).Should(WithTransform(
+			func(c *metav1.Condition) string { return c.Message }, ContainSubstring("no SecretGrant")))
+		Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.Labels).NotTo(HaveKey("app"))
+	})
+})
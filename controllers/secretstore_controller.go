@@ -18,14 +18,34 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/go-logr/logr"
-	"github.com/secrets-operator/secrets-operator/pkg/clients/kube"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	"github.com/secrets-operator/secrets-operator/pkg/credentials"
 	"github.com/secrets-operator/secrets-operator/pkg/deployment"
-	"github.com/secrets-operator/secrets-operator/pkg/secretstores/gcp"
+	"github.com/secrets-operator/secrets-operator/pkg/metrics"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/service"
 	"github.com/secrets-operator/secrets-operator/pkg/serviceaccount"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"time"
 
 	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
@@ -36,19 +56,37 @@ type SecretStoreReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// StoreImage is the image of store deployments that do not override it
+	StoreImage string
+	// Authority issues the serving certificates of store agents
+	Authority *certificates.Authority
+	// DefaultMode applies to stores that do not set spec.mode
+	DefaultMode secretoperatorv1alpha1.StoreMode
+	// Recorder records the events of stores
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=secret-operator.io,resources=secretstores,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=secret-operator.io,resources=secretstores/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;create;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-func (r *SecretStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithValues("secretstore", req.NamespacedName)
+func (r *SecretStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "SecretStore.Reconcile",
+		attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+	log := tracing.Logger(ctx, r.Log.WithValues("secretstore", req.NamespacedName))
 
 	var store secretoperatorv1alpha1.SecretStore
 	if err := r.Get(ctx, req.NamespacedName, &store); err != nil {
-		log.Error(err, "unable to fetch CronJob")
+		log.Error(err, "unable to fetch SecretStore")
+		if apierrors.IsNotFound(err) {
+			deleted := &secretoperatorv1alpha1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+			forgetStoreMetrics(deleted, req.Namespace)
+		}
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
@@ -60,31 +98,200 @@ func (r *SecretStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// For Azure this will mean creating a deployment with specific pod annotations for use with aad-pod-identity
 	// For AWS this will mean using IRSA service account annotations
 
-	kubeClient, err := kube.CreateClientSet()
+	if err := validateNamespacedStore(&store, r.DefaultMode); err != nil {
+		log.Error(err, "invalid store")
+		return ctrl.Result{}, updateStoreStatus(ctx, r.Client, r.Recorder, &store, EventValidationFailed, err)
+	}
+
+	if err := validateCredentials(ctx, r.Client, &store); err != nil {
+		log.Error(err, "invalid store credentials")
+		if err := updateStoreStatus(ctx, r.Client, r.Recorder, &store, failureReason(err), err); err != nil {
+			log.Error(err, "unable to update store status")
+		}
+		return resultFor(err)
+	}
+
+	if store.Spec.ModeOr(r.DefaultMode) == secretoperatorv1alpha1.StoreModeInProcess {
+		if err := removeStoreDeployment(ctx, r.Client, &store, store.Namespace); err != nil {
+			log.Error(err, "unable to remove store deployment")
+			r.Recorder.Eventf(&store, corev1.EventTypeWarning, EventSyncFailed, "Unable to remove store deployment: %v", err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, updateStoreStatus(ctx, r.Client, r.Recorder, &store, "", nil)
+	}
+
+	renewal, err := reconcileStoreDeployment(ctx, r.Client, r.Scheme, r.Authority, &store, store.Namespace, r.StoreImage)
 	if err != nil {
-		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+		log.Error(err, "unable to reconcile store deployment")
+		if err := updateStoreStatus(ctx, r.Client, r.Recorder, &store, failureReason(err), err); err != nil {
+			log.Error(err, "unable to update store status")
+		}
+		return resultFor(err)
 	}
 
-	if store.Spec.Provider.GcpSecretsManager != nil && store.Spec.Provider.GcpSecretsManager.Auth.WorkloadIdentity != nil {
-		expectedServiceAccount := gcp.GcpServiceAccount(store)
-		err = serviceaccount.Reconcile(kubeClient, expectedServiceAccount, &store)
-		if err != nil {
-			return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	return renewalResult(renewal), updateStoreStatus(ctx, r.Client, r.Recorder, &store, "", nil)
+}
+
+// validateNamespacedStore repeats the checks of the SecretStore webhook the reconcilers rely on,
+// as the webhook may be disabled: the store only references its own namespace and does not use
+// the operator's identity when it runs in process.
+func validateNamespacedStore(store *secretoperatorv1alpha1.SecretStore, defaultMode secretoperatorv1alpha1.StoreMode) error {
+	allErrs := append(store.ValidateNamespaces(), store.ValidateInProcess(defaultMode)...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return retry.Terminal(fmt.Errorf("secret store %s/%s: %w", store.Namespace, store.Name, allErrs.ToAggregate()))
+}
+
+// validateCredentials checks the resolved credentials of the store if its provider supports it.
+// Malformed credentials are terminal, the store is reconciled again when the Secrets holding them
+// change. Credentials that could not be read are retried.
+func validateCredentials(ctx context.Context, c client.Client, store secretoperatorv1alpha1.GenericStore) error {
+	provider, err := providers.ForSpec(&store.GetSpec().Provider)
+	if err != nil {
+		return err
+	}
+	validator, ok := provider.(providers.CredentialsValidator)
+	if !ok {
+		return nil
+	}
+	err = validator.ValidateCredentials(ctx, &store.GetSpec().Provider, credentials.SecretResolver(c))
+	if errors.Is(err, providers.ErrMalformedCredentials) {
+		return retry.Terminal(err)
+	}
+	return err
+}
+
+// updateStoreStatus records the outcome of reconciling the store, err is nil on success. Changes
+// of the status are recorded as events, failures with reason.
+func updateStoreStatus(ctx context.Context, c client.Client, recorder record.EventRecorder, store secretoperatorv1alpha1.GenericStore,
+	reason string, err error) error {
+	status := secretoperatorv1alpha1.SecretStoreStatus{Ready: err == nil}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	if *store.GetStatus() == status {
+		return nil
+	}
+	if err != nil {
+		recorder.Event(store, corev1.EventTypeWarning, reason, status.Message)
+	} else {
+		recorder.Event(store, corev1.EventTypeNormal, EventReady, "Store is ready")
+	}
+	*store.GetStatus() = status
+	return c.Status().Update(ctx, store)
+}
+
+// reconcileStoreDeployment provisions the service account, serving certificate, deployment and
+// service backing a store in namespace. It returns the time the serving certificate is due for renewal.
+func reconcileStoreDeployment(ctx context.Context, c client.Client, scheme *runtime.Scheme, authority *certificates.Authority,
+	store secretoperatorv1alpha1.GenericStore, namespace string, image string) (time.Time, error) {
+	provider, err := providers.ForSpec(&store.GetSpec().Provider)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if expectedServiceAccount := provider.ServiceAccount(store, namespace); expectedServiceAccount != nil {
+		if err := serviceaccount.Reconcile(ctx, c, scheme, *expectedServiceAccount, store); err != nil {
+			return time.Time{}, err
 		}
 	}
 
-	deploymentParams := deployment.DeploymentParams(store)
-	expectedDeployment := deployment.New(deploymentParams)
-	err = deployment.Reconcile(kubeClient, expectedDeployment, &store)
+	name := deployment.Name(store)
+	credentialsHash, err := credentials.ReconcileSecret(ctx, c, scheme, store, name, namespace)
+	if err != nil {
+		return time.Time{}, err
+	}
+	deploymentParams, err := deployment.DeploymentParams(store, namespace, image, credentialsHash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	renewal, err := certificates.ReconcileServingSecret(ctx, c, scheme, authority, deploymentParams.Name, namespace, store)
 	if err != nil {
-		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+		return time.Time{}, err
+	}
+	expectedDeployment := deployment.New(deploymentParams)
+	if err := deployment.Reconcile(ctx, c, scheme, expectedDeployment, store); err != nil {
+		return time.Time{}, err
+	}
+
+	// Claims reach the store agent through a service named after the deployment.
+	expectedService := service.New(deploymentParams.Name, namespace, deploymentParams.Selector, storeagent.Port, "https")
+	return renewal, service.Reconcile(ctx, c, scheme, expectedService, store)
+}
+
+// removeStoreDeployment deletes the objects reconcileStoreDeployment created for a store in namespace,
+// e.g. after the store switched to InProcess mode.
+func removeStoreDeployment(ctx context.Context, c client.Client, store secretoperatorv1alpha1.GenericStore, namespace string) error {
+	forgetStoreMetrics(store, namespace)
+	var deployments appsv1.DeploymentList
+	var services corev1.ServiceList
+	var secrets corev1.SecretList
+	var serviceAccounts corev1.ServiceAccountList
+	for _, list := range []client.ObjectList{&deployments, &services, &secrets, &serviceAccounts} {
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			object := item.(client.Object)
+			if !metav1.IsControlledBy(object, store) {
+				continue
+			}
+			if err := c.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// forgetStoreMetrics removes the serving certificate expiry of a store deployment in namespace
+// that no longer exists, so it does not trigger expiry alerts
+func forgetStoreMetrics(store secretoperatorv1alpha1.GenericStore, namespace string) {
+	metrics.ForgetCertificate(metrics.CertificateServing, namespace, certificates.ServingSecretName(deployment.Name(store)))
+}
+
+// storesForSecret enqueues the stores in the namespace of a secret that reference it, so
+// their store agents restart with the new credentials.
+func (r *SecretStoreReconciler) storesForSecret(object client.Object) []reconcile.Request {
+	var stores secretoperatorv1alpha1.SecretStoreList
+	if err := r.List(context.Background(), &stores, client.InNamespace(object.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list secret stores", "namespace", object.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range stores.Items {
+		if referencesSecret(&stores.Items[i], object) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: stores.Items[i].Namespace,
+				Name:      stores.Items[i].Name,
+			}})
+		}
 	}
+	return requests
+}
 
-	return ctrl.Result{}, nil
+// referencesSecret reports whether a secretRef of the store points at secret
+func referencesSecret(store secretoperatorv1alpha1.GenericStore, secret client.Object) bool {
+	for _, ref := range secretoperatorv1alpha1.SecretRefs(&store.GetSpec().Provider) {
+		if ref.Namespace == secret.GetNamespace() && ref.Name == secret.GetName() {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *SecretStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&secretoperatorv1alpha1.SecretStore{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.storesForSecret)).
+		WithOptions(controller.Options{RateLimiter: retry.NewRateLimiter()}).
 		Complete(r)
 }
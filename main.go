@@ -17,7 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,9 +33,16 @@ import (
 
 	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/controllers"
+	"github.com/secrets-operator/secrets-operator/pkg/audit"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	"github.com/secrets-operator/secrets-operator/pkg/deployment"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/all"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
+const defaultOperatorNamespace = "secret-operator-system"
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -46,13 +58,63 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var operatorNamespace string
+	var storeImage string
+	var storeMode string
+	var auditFile string
+	var auditStdout bool
+	var auditWebhook string
+	var auditKeyFile string
+	tracingOpts := tracing.Options{ServiceName: "secret-operator"}
+	tracingOpts.BindFlags(flag.CommandLine)
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&operatorNamespace, "operator-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace the operator runs in. Store deployments for ClusterSecretStores are created here.")
+	flag.StringVar(&storeImage, "store-image", deployment.StoreAgentImage,
+		"The image of store deployments. A store's spec.deployment.image takes precedence.")
+	flag.StringVar(&storeMode, "store-mode", string(secretoperatorv1alpha1.StoreModeAgent),
+		"The mode of stores that do not set spec.mode: Agent runs a store agent deployment per store, "+
+			"InProcess calls the provider from the operator.")
+	flag.StringVar(&auditFile, "audit-file", "", "Append audit entries of secret operations to this JSON lines file.")
+	flag.BoolVar(&auditStdout, "audit-stdout", false, "Write audit entries of secret operations to stdout.")
+	flag.StringVar(&auditWebhook, "audit-webhook-url", "", "Post audit entries of secret operations to this URL.")
+	flag.StringVar(&auditKeyFile, "audit-fingerprint-key-file", "",
+		"A file holding the key of the value fingerprints and the hash chain of audit entries. "+
+			"Required with --audit-file. Without it a random key is used and fingerprints can only be compared within one run.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.Parse()
 
+	if operatorNamespace == "" {
+		operatorNamespace = defaultOperatorNamespace
+	}
+
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	ctx := ctrl.SetupSignalHandler()
+
+	defaultStoreMode := secretoperatorv1alpha1.StoreMode(storeMode)
+	if defaultStoreMode != secretoperatorv1alpha1.StoreModeAgent && defaultStoreMode != secretoperatorv1alpha1.StoreModeInProcess {
+		setupLog.Error(fmt.Errorf("unknown store mode %q", storeMode), "invalid --store-mode")
+		os.Exit(1)
+	}
+
+	auditLogger, err := newAuditLogger(auditFile, auditStdout, auditWebhook, auditKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to set up audit log")
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
@@ -66,27 +128,126 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Requests of the reconcilers to the API server are recorded as spans.
+	tracedClient := tracing.WrapClient(mgr.GetClient())
+
+	// The internal CA secures the connections between the manager and the store agents.
+	authority := certificates.NewAuthority(mgr.GetClient(), mgr.GetAPIReader(), operatorNamespace)
+
 	if err = (&controllers.SecretClaimReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("SecretClaim"),
-		Scheme: mgr.GetScheme(),
+		Client:            tracedClient,
+		Log:               ctrl.Log.WithName("controllers").WithName("SecretClaim"),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+		Authority:         authority,
+		DefaultStoreMode:  defaultStoreMode,
+		Recorder:          mgr.GetEventRecorderFor("secretclaim-controller"),
+		Audit:             auditLogger,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SecretClaim")
 		os.Exit(1)
 	}
-	if err = (&controllers.SecretStoreReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("SecretStore"),
+	if err = (&controllers.ClusterSecretClaimReconciler{
+		Client: tracedClient,
+		Log:    ctrl.Log.WithName("controllers").WithName("ClusterSecretClaim"),
 		Scheme: mgr.GetScheme(),
+		Audit:  auditLogger,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecretClaim")
+		os.Exit(1)
+	}
+	if err = (&controllers.SecretStoreReconciler{
+		Client:      tracedClient,
+		Log:         ctrl.Log.WithName("controllers").WithName("SecretStore"),
+		Scheme:      mgr.GetScheme(),
+		StoreImage:  storeImage,
+		Authority:   authority,
+		DefaultMode: defaultStoreMode,
+		Recorder:    mgr.GetEventRecorderFor("secretstore-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SecretStore")
 		os.Exit(1)
 	}
+	if err = (&controllers.ClusterSecretStoreReconciler{
+		Client:            tracedClient,
+		Log:               ctrl.Log.WithName("controllers").WithName("ClusterSecretStore"),
+		Scheme:            mgr.GetScheme(),
+		OperatorNamespace: operatorNamespace,
+		StoreImage:        storeImage,
+		Authority:         authority,
+		DefaultMode:       defaultStoreMode,
+		Recorder:          mgr.GetEventRecorderFor("clustersecretstore-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecretStore")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&secretoperatorv1alpha1.SecretClaim{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SecretClaim")
+			os.Exit(1)
+		}
+		if err = (&secretoperatorv1alpha1.ClusterSecretClaim{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSecretClaim")
+			os.Exit(1)
+		}
+		if err = (&secretoperatorv1alpha1.SecretStore{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SecretStore")
+			os.Exit(1)
+		}
+		if err = (&secretoperatorv1alpha1.ClusterSecretStore{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSecretStore")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	auditLogger.Close()
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// newAuditLogger returns a logger writing to the configured sinks, or nil if none is configured.
+// The chain continues after the last entry of the audit file, which needs the key it was written with.
+func newAuditLogger(file string, stdout bool, webhook string, keyFile string) (*audit.Logger, error) {
+	if file == "" && !stdout && webhook == "" {
+		return nil, nil
+	}
+
+	var key []byte
+	if keyFile != "" {
+		var err error
+		if key, err = ioutil.ReadFile(keyFile); err != nil {
+			return nil, err
+		}
+	} else {
+		if file != "" {
+			return nil, errors.New("--audit-file requires --audit-fingerprint-key-file to verify the chain after a restart")
+		}
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		setupLog.Info("no audit fingerprint key configured, fingerprints are only comparable within this run")
+	}
+
+	var sinks []audit.Sink
+	var last *audit.Entry
+	if file != "" {
+		fileSink, lastEntry, err := audit.OpenFileSink(file, key)
+		if err != nil {
+			return nil, err
+		}
+		sinks, last = append(sinks, fileSink), lastEntry
+	}
+	if stdout {
+		sinks = append(sinks, audit.NewWriterSink(os.Stdout))
+	}
+	if webhook != "" {
+		sinks = append(sinks, audit.NewWebhookSink(webhook))
+	}
+	return audit.NewLogger(key, last, ctrl.Log.WithName("audit"), sinks...), nil
+}
@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// queueSize is the number of entries waiting for the sinks before Record blocks
+const queueSize = 1024
+
+// Action is the operation an entry records
+type Action string
+
+const (
+	// ActionGenerate is a value generated for a property that had none
+	ActionGenerate Action = "generate"
+	// ActionRotate is a generated value replacing a previous one
+	ActionRotate Action = "rotate"
+	// ActionRead is a value read from a secret store
+	ActionRead Action = "read"
+	// ActionWrite is a value written to a destination
+	ActionWrite Action = "write"
+)
+
+// Actor is the claim an operation was performed for
+type Actor struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Event is an operation on a single secret value
+type Event struct {
+	Actor  Actor
+	Action Action
+	// Destination is the store or Secret the value was read from or written to, empty for generation
+	Destination string
+	Property    string
+	Value       []byte
+}
+
+// Entry is a recorded event. Each entry includes the hash of the previous one, so removing,
+// reordering or changing entries breaks the chain, see Verify. The hashes are HMACs keyed with
+// the logger's key: without it an entry cannot be forged, but anyone holding the key can
+// rewrite the whole chain, and truncating the end of the chain is only detected by comparing
+// the last sequence number with a copy kept elsewhere, e.g. by a webhook sink.
+type Entry struct {
+	Sequence    uint64    `json:"sequence"`
+	Time        time.Time `json:"time"`
+	Actor       Actor     `json:"actor"`
+	Action      Action    `json:"action"`
+	Destination string    `json:"destination,omitempty"`
+	Property    string    `json:"property"`
+	// Fingerprint identifies the value without revealing it: a truncated HMAC-SHA256 keyed
+	// with the logger's key
+	Fingerprint  string `json:"fingerprint"`
+	PreviousHash string `json:"previousHash"`
+	Hash         string `json:"hash"`
+}
+
+// computeHash returns the HMAC-SHA256 of the entry keyed with key, covering every field but Hash
+func (e Entry) computeHash(key []byte) (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Sink stores or forwards entries
+type Sink interface {
+	Write(ctx context.Context, entry Entry) error
+}
+
+// Logger chains events into entries and writes them to every sink. A nil Logger records nothing.
+type Logger struct {
+	sinks []Sink
+	// key keys both the fingerprints and the hashes of the chain
+	key []byte
+	now func() time.Time
+	log logr.Logger
+
+	mu       sync.Mutex
+	sequence uint64
+	lastHash string
+	closed   bool
+
+	queue chan Entry
+	done  chan struct{}
+}
+
+// NewLogger returns a logger continuing the chain after last, which is nil to start a new chain.
+// Entries are written to the sinks in the background and write failures are logged to log.
+// Close the logger to write the remaining entries.
+func NewLogger(key []byte, last *Entry, log logr.Logger, sinks ...Sink) *Logger {
+	l := &Logger{
+		sinks: sinks,
+		key:   key,
+		now:   time.Now,
+		log:   log,
+		queue: make(chan Entry, queueSize),
+		done:  make(chan struct{}),
+	}
+	if last != nil {
+		l.sequence, l.lastHash = last.Sequence, last.Hash
+	}
+	go l.run()
+	return l
+}
+
+// Record chains an entry for every event and queues it for the sinks. It only waits for the
+// sinks if queueSize entries are already waiting.
+func (l *Logger) Record(ctx context.Context, events ...Event) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return errors.New("audit logger is closed")
+	}
+	for _, event := range events {
+		entry := Entry{
+			Sequence:     l.sequence + 1,
+			Time:         l.now().UTC(),
+			Actor:        event.Actor,
+			Action:       event.Action,
+			Destination:  event.Destination,
+			Property:     event.Property,
+			Fingerprint:  l.fingerprint(event.Value),
+			PreviousHash: l.lastHash,
+		}
+		hash, err := entry.computeHash(l.key)
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+		l.sequence, l.lastHash = entry.Sequence, entry.Hash
+		// Queued under the lock, so the sinks receive the entries in chain order.
+		l.queue <- entry
+	}
+	return nil
+}
+
+// Close writes the queued entries to the sinks and stops the logger
+func (l *Logger) Close() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+	<-l.done
+}
+
+// run writes the queued entries to the sinks. The entries outlive the reconciles that
+// recorded them, so they are written without their contexts.
+func (l *Logger) run() {
+	defer close(l.done)
+	for entry := range l.queue {
+		for _, sink := range l.sinks {
+			if err := sink.Write(context.Background(), entry); err != nil {
+				l.log.Error(err, "unable to write audit entry", "sequence", entry.Sequence)
+			}
+		}
+	}
+}
+
+func (l *Logger) fingerprint(value []byte) string {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Verify checks the chain of entries written by a logger with key, which must be consecutive.
+// It returns the last entry.
+func Verify(key []byte, entries []Entry) (*Entry, error) {
+	var previous *Entry
+	for i := range entries {
+		entry := &entries[i]
+		hash, err := entry.computeHash(key)
+		if err != nil {
+			return nil, err
+		}
+		if hash != entry.Hash {
+			return nil, fmt.Errorf("entry %d: hash mismatch, the entry was modified", entry.Sequence)
+		}
+		if previous != nil {
+			if entry.Sequence != previous.Sequence+1 {
+				return nil, fmt.Errorf("entry %d: follows entry %d, entries are missing", entry.Sequence, previous.Sequence)
+			}
+			if entry.PreviousHash != previous.Hash {
+				return nil, fmt.Errorf("entry %d: previous hash mismatch, the chain was altered", entry.Sequence)
+			}
+		}
+		previous = entry
+	}
+	return previous, nil
+}
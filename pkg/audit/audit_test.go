@@ -0,0 +1,169 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+var testKey = []byte("test key")
+
+// memorySink keeps the entries written to it
+type memorySink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (s *memorySink) Write(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// blockingSink waits for release before writing
+type blockingSink struct {
+	memorySink
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(ctx context.Context, entry Entry) error {
+	<-s.release
+	return s.memorySink.Write(ctx, entry)
+}
+
+func testEvent(property string) Event {
+	return Event{
+		Actor:    Actor{Kind: "SecretClaim", Namespace: "default", Name: "app"},
+		Action:   ActionGenerate,
+		Property: property,
+		Value:    []byte("value of " + property),
+	}
+}
+
+// recordEntries returns the entries of a new chain of n events
+func recordEntries(t *testing.T, n int) []Entry {
+	t.Helper()
+	sink := &memorySink{}
+	logger := NewLogger(testKey, nil, logr.Discard(), sink)
+	for i := 0; i < n; i++ {
+		if err := logger.Record(context.Background(), testEvent(string(rune('a'+i)))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger.Close()
+	if len(sink.entries) != n {
+		t.Fatalf("got %d entries, want %d", len(sink.entries), n)
+	}
+	return sink.entries
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		alter   func(entries []Entry) []Entry
+		wantErr string
+	}{
+		{
+			name:  "unaltered",
+			key:   testKey,
+			alter: func(entries []Entry) []Entry { return entries },
+		},
+		{
+			name: "modified",
+			key:  testKey,
+			alter: func(entries []Entry) []Entry {
+				entries[1].Property = "other"
+				return entries
+			},
+			wantErr: "entry 2: hash mismatch",
+		},
+		{
+			name: "modified and rehashed without the key",
+			key:  testKey,
+			alter: func(entries []Entry) []Entry {
+				entries[1].Property = "other"
+				entries[1].Hash, _ = entries[1].computeHash([]byte("other key"))
+				entries[2].PreviousHash = entries[1].Hash
+				return entries
+			},
+			wantErr: "entry 2: hash mismatch",
+		},
+		{
+			name: "reordered",
+			key:  testKey,
+			alter: func(entries []Entry) []Entry {
+				entries[1], entries[2] = entries[2], entries[1]
+				return entries
+			},
+			wantErr: "entry 3: follows entry 1",
+		},
+		{
+			name: "missing",
+			key:  testKey,
+			alter: func(entries []Entry) []Entry {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantErr: "entry 3: follows entry 1",
+		},
+		{
+			name:    "other key",
+			key:     []byte("other key"),
+			alter:   func(entries []Entry) []Entry { return entries },
+			wantErr: "entry 1: hash mismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.alter(recordEntries(t, 4))
+			last, err := Verify(tt.key, entries)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if last.Sequence != 4 {
+					t.Errorf("got last entry %d, want 4", last.Sequence)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecordDoesNotWaitForSinks(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	logger := NewLogger(testKey, nil, logr.Discard(), sink)
+
+	recorded := make(chan error)
+	go func() {
+		recorded <- logger.Record(context.Background(), testEvent("a"), testEvent("b"))
+	}()
+	select {
+	case err := <-recorded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record waited for the sink")
+	}
+
+	close(sink.release)
+	logger.Close()
+	if _, err := Verify(testKey, sink.entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.entries) != 2 {
+		t.Errorf("got %d entries after Close, want 2", len(sink.entries))
+	}
+	if err := logger.Record(context.Background(), testEvent("c")); err == nil {
+		t.Error("Record succeeded after Close")
+	}
+}
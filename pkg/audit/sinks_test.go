@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+// recordToFile records events to the audit log at path, continuing its chain
+func recordToFile(t *testing.T, path string, properties ...string) {
+	t.Helper()
+	sink, last, err := OpenFileSink(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	logger := NewLogger(testKey, last, logr.Discard(), sink)
+	for _, property := range properties {
+		if err := logger.Record(context.Background(), testEvent(property)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger.Close()
+}
+
+func TestOpenFileSinkContinuesTheChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	recordToFile(t, path, "a", "b")
+	recordToFile(t, path, "c")
+
+	entries, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := Verify(testKey, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || last.Sequence != 3 {
+		t.Fatalf("got %d entries ending with %d, want 3", len(entries), last.Sequence)
+	}
+	if entries[2].PreviousHash != entries[1].Hash {
+		t.Error("the entry of the second run does not chain to the first run")
+	}
+
+	sink, last, err := OpenFileSink(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Close()
+	if last.Hash != entries[2].Hash {
+		t.Errorf("got last entry %d, want 3", last.Sequence)
+	}
+}
+
+func TestOpenFileSinkRejectsAlteredLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	recordToFile(t, path, "a", "b")
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	altered := strings.Replace(string(data), `"property":"a"`, `"property":"x"`, 1)
+	if err := ioutil.WriteFile(path, []byte(altered), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := OpenFileSink(path, testKey); err == nil || !strings.Contains(err.Error(), "entry 1: hash mismatch") {
+		t.Errorf("got error %v, want a hash mismatch of entry 1", err)
+	}
+}
@@ -1,6 +1,9 @@
 package builders
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
 
 type PodTemplateBuilder struct {
 	PodTemplate corev1.PodTemplateSpec
@@ -35,18 +38,118 @@ func (b *PodTemplateBuilder) WithImage(image string) *PodTemplateBuilder {
 
 // WithEnv appends the given env vars to the Container, unless already provided in the template.
 func (b *PodTemplateBuilder) WithEnv(vars ...corev1.EnvVar) *PodTemplateBuilder {
-	b.PodTemplate.Spec.Containers[0].Env = vars
+	container := &b.PodTemplate.Spec.Containers[0]
+	for _, v := range vars {
+		if !hasEnv(container.Env, v.Name) {
+			container.Env = append(container.Env, v)
+		}
+	}
 	return b
 }
 
+func hasEnv(vars []corev1.EnvVar, name string) bool {
+	for _, v := range vars {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // WithLabels sets the given labels, but does not override those that already exist.
 func (b *PodTemplateBuilder) WithLabels(labels map[string]string) *PodTemplateBuilder {
-	b.PodTemplate.Labels = labels
+	for k, v := range labels {
+		if b.PodTemplate.Labels == nil {
+			b.PodTemplate.Labels = map[string]string{}
+		}
+		if _, ok := b.PodTemplate.Labels[k]; !ok {
+			b.PodTemplate.Labels[k] = v
+		}
+	}
 	return b
 }
 
 // WithAnnotations sets the given annotations, but does not override those that already exist.
 func (b *PodTemplateBuilder) WithAnnotations(annotations map[string]string) *PodTemplateBuilder {
-	b.PodTemplate.Annotations = annotations
+	for k, v := range annotations {
+		if b.PodTemplate.Annotations == nil {
+			b.PodTemplate.Annotations = map[string]string{}
+		}
+		if _, ok := b.PodTemplate.Annotations[k]; !ok {
+			b.PodTemplate.Annotations[k] = v
+		}
+	}
+	return b
+}
+
+func (b *PodTemplateBuilder) WithPorts(ports ...corev1.ContainerPort) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Containers[0].Ports = append(b.PodTemplate.Spec.Containers[0].Ports, ports...)
+	return b
+}
+
+func (b *PodTemplateBuilder) WithReadinessProbe(probe *corev1.Probe) *PodTemplateBuilder {
+	if b.PodTemplate.Spec.Containers[0].ReadinessProbe == nil {
+		b.PodTemplate.Spec.Containers[0].ReadinessProbe = probe
+	}
+	return b
+}
+
+// WithVolume adds the volume to the pod and mounts it into the Container.
+func (b *PodTemplateBuilder) WithVolume(volume corev1.Volume, mount corev1.VolumeMount) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Volumes = append(b.PodTemplate.Spec.Volumes, volume)
+	b.PodTemplate.Spec.Containers[0].VolumeMounts = append(b.PodTemplate.Spec.Containers[0].VolumeMounts, mount)
+	return b
+}
+
+func (b *PodTemplateBuilder) WithImagePullSecrets(secrets []corev1.LocalObjectReference) *PodTemplateBuilder {
+	b.PodTemplate.Spec.ImagePullSecrets = append(b.PodTemplate.Spec.ImagePullSecrets, secrets...)
+	return b
+}
+
+func (b *PodTemplateBuilder) WithResources(resources corev1.ResourceRequirements) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Containers[0].Resources = resources
+	return b
+}
+
+func (b *PodTemplateBuilder) WithNodeSelector(nodeSelector map[string]string) *PodTemplateBuilder {
+	b.PodTemplate.Spec.NodeSelector = nodeSelector
+	return b
+}
+
+func (b *PodTemplateBuilder) WithTolerations(tolerations []corev1.Toleration) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Tolerations = append(b.PodTemplate.Spec.Tolerations, tolerations...)
+	return b
+}
+
+func (b *PodTemplateBuilder) WithAffinity(affinity *corev1.Affinity) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Affinity = affinity
+	return b
+}
+
+func (b *PodTemplateBuilder) WithSecurityContext(securityContext *corev1.SecurityContext) *PodTemplateBuilder {
+	b.PodTemplate.Spec.Containers[0].SecurityContext = securityContext
+	return b
+}
+
+func (b *PodTemplateBuilder) WithPodSecurityContext(securityContext *corev1.PodSecurityContext) *PodTemplateBuilder {
+	b.PodTemplate.Spec.SecurityContext = securityContext
 	return b
 }
+
+// WithDeploymentOverrides applies the store's deployment overrides. It must be called before
+// the defaults are applied, since the With* defaults do not replace values that are already set.
+func (b *PodTemplateBuilder) WithDeploymentOverrides(overrides *v1alpha1.StoreDeployment) *PodTemplateBuilder {
+	if overrides == nil {
+		return b
+	}
+	return b.WithImage(overrides.Image).
+		WithImagePullSecrets(overrides.ImagePullSecrets).
+		WithResources(overrides.Resources).
+		WithNodeSelector(overrides.NodeSelector).
+		WithTolerations(overrides.Tolerations).
+		WithAffinity(overrides.Affinity).
+		WithSecurityContext(overrides.SecurityContext).
+		WithPodSecurityContext(overrides.PodSecurityContext).
+		WithEnv(overrides.Env...).
+		WithAnnotations(overrides.Annotations)
+}
@@ -0,0 +1,172 @@
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CASecretName is the Secret in the operator namespace holding the internal CA
+	CASecretName = "secret-operator-ca"
+	// CABundleKey holds the PEM encoded certificates of the current and previous CAs
+	CABundleKey = "ca.crt"
+	// ManagerCommonName is the subject of the manager's client certificates
+	ManagerCommonName = "secret-operator-manager"
+)
+
+// Authority is the operator's internal CA. It issues the serving certificates of store
+// agents and the client certificates the manager presents to them, and replaces the CA
+// before it expires. Certificates of the previous CA are trusted until it expires.
+type Authority struct {
+	client    client.Client
+	reader    client.Reader
+	namespace string
+
+	mu         sync.Mutex
+	ca         *KeyPair
+	bundle     []byte
+	clientCert *KeyPair
+	transport  *http.Transport
+	// transportBundle is the CA bundle the transport trusts
+	transportBundle []byte
+}
+
+// NewAuthority returns an authority storing its CA in the operator namespace. The reader
+// should not be cached, so concurrently created CAs are noticed.
+func NewAuthority(c client.Client, reader client.Reader, namespace string) *Authority {
+	return &Authority{client: c, reader: reader, namespace: namespace}
+}
+
+// Bundle returns the PEM encoded certificates of the trusted CAs
+func (a *Authority) Bundle(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.ensureCA(ctx); err != nil {
+		return nil, err
+	}
+	return a.bundle, nil
+}
+
+// IssueServing issues a serving certificate for the DNS names and returns it with the CA bundle
+func (a *Authority) IssueServing(ctx context.Context, dnsNames []string) (*KeyPair, []byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.ensureCA(ctx); err != nil {
+		return nil, nil, err
+	}
+	cert, err := IssueServing(a.ca, dnsNames, time.Now())
+	return cert, a.bundle, err
+}
+
+// HTTPClient returns a client presenting the manager's client certificate and trusting
+// only servers with certificates issued by the authority
+func (a *Authority) HTTPClient(ctx context.Context) (*http.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.ensureCA(ctx); err != nil {
+		return nil, err
+	}
+	if a.transport == nil || !bytes.Equal(a.transportBundle, a.bundle) {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(a.bundle)
+		a.transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion:           tls.VersionTLS12,
+				RootCAs:              pool,
+				GetClientCertificate: a.clientCertificate,
+			},
+		}
+		a.transportBundle = a.bundle
+	}
+	return &http.Client{Transport: a.transport, Timeout: 30 * time.Second}, nil
+}
+
+// clientCertificate returns the manager's client certificate, issuing a new one when the
+// current one is due for renewal or was issued by a previous CA
+func (a *Authority) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.clientCert == nil || NeedsRenewal(a.clientCert.Cert, now) || a.clientCert.Cert.CheckSignatureFrom(a.ca.Cert) != nil {
+		cert, err := IssueClient(a.ca, ManagerCommonName, now)
+		if err != nil {
+			return nil, err
+		}
+		a.clientCert = cert
+		metrics.SetCertificateExpiry(metrics.CertificateClient, a.namespace, ManagerCommonName, cert.Cert.NotAfter)
+	}
+	return a.clientCert.TLSCertificate(), nil
+}
+
+// ensureCA loads the CA from its Secret, creating or replacing it when missing or due for renewal.
+// The caller must hold a.mu.
+func (a *Authority) ensureCA(ctx context.Context) error {
+	now := time.Now()
+	if a.ca != nil && !NeedsRenewal(a.ca.Cert, now) {
+		return nil
+	}
+
+	var secret corev1.Secret
+	err := a.reader.Get(ctx, types.NamespacedName{Namespace: a.namespace, Name: CASecretName}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get CA secret: %w", err)
+	}
+	exists := err == nil
+	if exists {
+		ca, err := ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && !NeedsRenewal(ca.Cert, now) {
+			a.ca, a.bundle = ca, secret.Data[CABundleKey]
+			metrics.SetCertificateExpiry(metrics.CertificateCA, a.namespace, CASecretName, ca.Cert.NotAfter)
+			return nil
+		}
+	}
+
+	ca, err := NewCA("secret-operator-ca", now)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		return err
+	}
+	bundle := ca.CertPEM()
+	if previous, err := ParseCertificates(secret.Data[CABundleKey]); err == nil {
+		for _, cert := range previous {
+			if cert.NotAfter.After(now) {
+				bundle = append(bundle, (&KeyPair{Cert: cert}).CertPEM()...)
+			}
+		}
+	}
+
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       ca.CertPEM(),
+		corev1.TLSPrivateKeyKey: keyPEM,
+		CABundleKey:             bundle,
+	}
+	if exists {
+		err = a.client.Update(ctx, &secret)
+	} else {
+		secret.ObjectMeta = metav1.ObjectMeta{Namespace: a.namespace, Name: CASecretName}
+		err = a.client.Create(ctx, &secret)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write CA secret: %w", err)
+	}
+	a.ca, a.bundle = ca, bundle
+	metrics.SetCertificateExpiry(metrics.CertificateCA, a.namespace, CASecretName, ca.Cert.NotAfter)
+	return nil
+}
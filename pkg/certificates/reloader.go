@@ -0,0 +1,98 @@
+package certificates
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// FileReloader serves the certificate, key and CA bundle of a mounted serving Secret,
+// picking up rotated files on the next handshake
+type FileReloader struct {
+	dir string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+}
+
+// NewFileReloader loads the serving Secret mounted in dir
+func NewFileReloader(dir string) (*FileReloader, error) {
+	r := &FileReloader{dir: dir}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ServerTLSConfig returns a config that only accepts clients presenting the manager's
+// certificate issued by a trusted CA
+func (r *FileReloader) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if err := r.reload(); err != nil {
+				return nil, err
+			}
+			return &tls.Config{
+				MinVersion:       tls.VersionTLS12,
+				Certificates:     []tls.Certificate{*r.cert},
+				ClientCAs:        r.pool,
+				ClientAuth:       tls.RequireAndVerifyClientCert,
+				VerifyConnection: verifyManager,
+			}, nil
+		},
+	}
+}
+
+func verifyManager(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 || state.PeerCertificates[0].Subject.CommonName != ManagerCommonName {
+		return errors.New("client certificate does not belong to the operator")
+	}
+	return nil
+}
+
+// reload reads the files if any of them changed since the last read. The caller must hold r.mu
+// unless r is not shared yet.
+func (r *FileReloader) reload() error {
+	files := []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, CABundleKey}
+	var modTime time.Time
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(r.dir, file))
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(r.dir, corev1.TLSCertKey), filepath.Join(r.dir, corev1.TLSPrivateKeyKey))
+	if err != nil {
+		return fmt.Errorf("unable to load serving certificate: %w", err)
+	}
+	bundle, err := ioutil.ReadFile(filepath.Join(r.dir, CABundleKey))
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return errors.New("no CA certificates found in bundle")
+	}
+	r.cert, r.pool, r.modTime = &cert, pool, modTime
+	return nil
+}
@@ -0,0 +1,167 @@
+package certificates
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testServerName = "agent.tenant.svc"
+
+func newCA(t *testing.T) *KeyPair {
+	t.Helper()
+	ca, err := NewCA("test-ca", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca
+}
+
+func issueClient(t *testing.T, ca *KeyPair, commonName string) *KeyPair {
+	t.Helper()
+	cert, err := IssueClient(ca, commonName, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// writeServingSecret writes a serving certificate of ca and the bundle of the trusted CAs to dir,
+// as the mounted serving Secret. modTime is set explicitly, so a rewrite is noticed even within
+// the resolution of the file system's timestamps.
+func writeServingSecret(t *testing.T, dir string, ca *KeyPair, modTime time.Time, trusted ...*KeyPair) {
+	t.Helper()
+	cert, err := IssueServing(ca, []string{testServerName}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := cert.KeyPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle []byte
+	for _, ca := range trusted {
+		bundle = append(bundle, ca.CertPEM()...)
+	}
+	files := map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM(),
+		corev1.TLSPrivateKeyKey: keyPEM,
+		CABundleKey:             bundle,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// startServer serves the certificates in dir the way the store agent does
+func startServer(t *testing.T, dir string) *httptest.Server {
+	t.Helper()
+	reloader, err := NewFileReloader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = reloader.ServerTLSConfig()
+	// Rejected handshakes are expected
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// get requests the server on a new connection, presenting cert unless it is nil and trusting roots
+func get(server *httptest.Server, cert *KeyPair, roots ...*KeyPair) error {
+	pool := x509.NewCertPool()
+	for _, root := range roots {
+		pool.AddCert(root.Cert)
+	}
+	config := &tls.Config{ServerName: testServerName, RootCAs: pool}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert.TLSCertificate()}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestServerTLSConfigVerifiesClients(t *testing.T) {
+	ca := newCA(t)
+	dir := t.TempDir()
+	writeServingSecret(t, dir, ca, time.Now(), ca)
+	server := startServer(t, dir)
+
+	tests := []struct {
+		name    string
+		cert    *KeyPair
+		wantErr bool
+	}{
+		{name: "manager certificate", cert: issueClient(t, ca, ManagerCommonName)},
+		{name: "no client certificate", cert: nil, wantErr: true},
+		{name: "other common name", cert: issueClient(t, ca, "someone-else"), wantErr: true},
+		{name: "untrusted CA", cert: issueClient(t, newCA(t), ManagerCommonName), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := get(server, tt.cert, ca)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerTLSConfigFollowsCARotation(t *testing.T) {
+	previous := newCA(t)
+	dir := t.TempDir()
+	written := time.Now().Add(-time.Hour)
+	writeServingSecret(t, dir, previous, written, previous)
+	server := startServer(t, dir)
+	previousClient := issueClient(t, previous, ManagerCommonName)
+	if err := get(server, previousClient, previous); err != nil {
+		t.Fatalf("before the rotation: %v", err)
+	}
+
+	// The rotated Secret is served by the new CA, which the bundle trusts along with the previous one
+	current := newCA(t)
+	writeServingSecret(t, dir, current, written.Add(time.Minute), current, previous)
+	currentClient := issueClient(t, current, ManagerCommonName)
+	if err := get(server, currentClient, current); err != nil {
+		t.Errorf("certificate of the new CA: %v", err)
+	}
+	if err := get(server, previousClient, current); err != nil {
+		t.Errorf("certificate of the previous CA while it is trusted: %v", err)
+	}
+	if err := get(server, currentClient, previous); err == nil {
+		t.Error("the client accepted a serving certificate of the new CA trusting only the previous one")
+	}
+
+	// Once the previous CA expired it is dropped from the bundle
+	writeServingSecret(t, dir, current, written.Add(2*time.Minute), current)
+	if err := get(server, previousClient, current); err == nil {
+		t.Error("certificate of the dropped CA was accepted")
+	}
+	if err := get(server, currentClient, current); err != nil {
+		t.Errorf("certificate of the new CA after dropping the previous one: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	"github.com/secrets-operator/secrets-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServingSecretName returns the name of the Secret holding the serving certificate of a store deployment
+func ServingSecretName(deployment string) string {
+	return deployment + "-tls"
+}
+
+// ServiceDNSNames returns the names a service in namespace is reached by
+func ServiceDNSNames(name, namespace string) []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+	}
+}
+
+// ReconcileServingSecret keeps a serving certificate for the service name in namespace and the
+// CA bundle in a Secret owned by owner. It returns the time the certificate is due for renewal.
+func ReconcileServingSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, authority *Authority, name, namespace string, owner client.Object) (time.Time, error) {
+	bundle, err := authority.Bundle(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var existing corev1.Secret
+	err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ServingSecretName(name)}, &existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return time.Time{}, fmt.Errorf("failed to get secret %s/%s: %w", namespace, ServingSecretName(name), err)
+	}
+	exists := err == nil
+	if exists {
+		// A Secret of the same name written by someone else is never replaced.
+		if err := controllerutil.CheckControlledBy(&existing, owner); err != nil {
+			return time.Time{}, fmt.Errorf("serving secret: %w", err)
+		}
+	}
+	if exists && bytes.Equal(existing.Data[CABundleKey], bundle) {
+		cert, err := ParseKeyPair(existing.Data[corev1.TLSCertKey], existing.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && !NeedsRenewal(cert.Cert, time.Now()) {
+			metrics.SetCertificateExpiry(metrics.CertificateServing, namespace, ServingSecretName(name), cert.Cert.NotAfter)
+			return RenewalTime(cert.Cert), nil
+		}
+	}
+
+	cert, bundle, err := authority.IssueServing(ctx, ServiceDNSNames(name, namespace))
+	if err != nil {
+		return time.Time{}, err
+	}
+	keyPEM, err := cert.KeyPEM()
+	if err != nil {
+		return time.Time{}, err
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ServingSecretName(name), Namespace: namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert.CertPEM(),
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CABundleKey:             bundle,
+		},
+	}
+	if err := controllerutil.SetControllerReference(owner, &secret, scheme); err != nil {
+		return time.Time{}, err
+	}
+	if exists {
+		secret.ResourceVersion = existing.ResourceVersion
+		err = c.Update(ctx, &secret)
+	} else {
+		err = c.Create(ctx, &secret)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to write secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	metrics.SetCertificateExpiry(metrics.CertificateServing, namespace, secret.Name, cert.Cert.NotAfter)
+	return RenewalTime(cert.Cert), nil
+}
@@ -0,0 +1,62 @@
+package certificates
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileServingSecret(t *testing.T) {
+	ctx := context.Background()
+	// Any namespaced object can own the Secret, stores do in the operator
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "tenant", UID: "store-uid"}}
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ServingSecretName("user"), Namespace: "tenant"},
+		Data:       map[string][]byte{"tls.crt": []byte("user certificate")},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(owner, userSecret).Build()
+	authority := NewAuthority(c, c, "operator")
+
+	renewal, err := ReconcileServingSecret(ctx, c, clientgoscheme.Scheme, authority, "agent", "tenant", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant", Name: ServingSecretName("agent")}, &secret); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(&secret, owner) {
+		t.Error("the serving secret is not controlled by its owner")
+	}
+
+	// An up to date certificate is kept
+	again, err := ReconcileServingSecret(ctx, c, clientgoscheme.Scheme, authority, "agent", "tenant", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Equal(renewal) {
+		t.Errorf("got renewal %v, want the kept certificate's %v", again, renewal)
+	}
+
+	// A Secret of the same name written by someone else is left alone
+	_, err = ReconcileServingSecret(ctx, c, clientgoscheme.Scheme, authority, "user", "tenant", owner)
+	if !errors.Is(err, controllerutil.ErrOwnershipConflict) {
+		t.Fatalf("got error %v, want %v", err, controllerutil.ErrOwnershipConflict)
+	}
+	var current corev1.Secret
+	if err := c.Get(ctx, client.ObjectKeyFromObject(userSecret), &current); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(current.Data, userSecret.Data) || len(current.OwnerReferences) > 0 {
+		t.Errorf("the user's secret was changed: %+v", current)
+	}
+}
@@ -0,0 +1,207 @@
+package clusterclaim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/source"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrPolicyViolation is returned for a selected namespace whose PasswordPolicy the template does not satisfy
+var ErrPolicyViolation = errors.New("template does not satisfy the password policy of the namespace")
+
+// Handler replicates the template Secret of a ClusterSecretClaim into every selected namespace.
+type Handler struct {
+	ctx        context.Context
+	claim      v1alpha1.ClusterSecretClaim
+	client     client.Client
+	namespaces []string
+	written    []string
+	generated  []v1alpha1.SecretClaimProperty
+	valuesTime time.Time
+	values     map[string][]byte
+}
+
+func NewHandler(claim v1alpha1.ClusterSecretClaim, ctx context.Context, c client.Client) *Handler {
+	return &Handler{ctx: ctx, claim: claim, client: c}
+}
+
+// Namespaces returns the namespaces the Secret was written to by the last call to Handle.
+func (h *Handler) Namespaces() []string {
+	return h.namespaces
+}
+
+// Written returns the namespaces whose Secret was created or changed by the last call to Handle.
+func (h *Handler) Written() []string {
+	return h.written
+}
+
+// Values returns the values written by the last call to Handle.
+func (h *Handler) Values() map[string][]byte {
+	return h.values
+}
+
+// Generated returns the properties the last call to Handle generated new values for.
+func (h *Handler) Generated() []v1alpha1.SecretClaimProperty {
+	return h.generated
+}
+
+// ValuesTime returns when the values kept by the last call to Handle were written, the
+// creation time of the replica they were read from. It is zero if no replica existed.
+func (h *Handler) ValuesTime() time.Time {
+	return h.valuesTime
+}
+
+func (h *Handler) Handle() error {
+	selected, err := h.selectedNamespaces()
+	if err != nil {
+		return err
+	}
+	owned, err := h.ownedSecrets()
+	if err != nil {
+		return err
+	}
+
+	// Properties are sourced once and the same values are written everywhere. Once a replica
+	// exists, namespaces that start matching later receive its values rather than new ones.
+	var existing map[string][]byte
+	h.valuesTime = time.Time{}
+	if len(owned) > 0 {
+		existing = kubernetesclaim.SecretValues(owned[0])
+		h.valuesTime = owned[0].CreationTimestamp.Time
+	}
+	var policies v1alpha1.PasswordPolicyList
+	if err := h.client.List(h.ctx, &policies); err != nil {
+		return fmt.Errorf("unable to list password policies: %w", err)
+	}
+
+	values, err := source.HandleProperties(h.ctx, h.claim.Spec.Template.Properties, existing, nil)
+	if err != nil {
+		return err
+	}
+	h.generated = source.GeneratedProperties(h.claim.Spec.Template.Properties, existing)
+	h.values = values
+
+	var errs []error
+	h.namespaces, h.written = nil, nil
+	selectedSet := map[string]bool{}
+	for _, namespace := range selected {
+		selectedSet[namespace.Name] = true
+		// The webhook checked the policies of the namespaces matching when the claim was admitted,
+		// a namespace may have started matching since.
+		violations, err := h.policyViolations(policies.Items, namespace)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("namespace %s: %w", namespace.Name, err))
+			continue
+		}
+		if len(violations) > 0 {
+			errs = append(errs, fmt.Errorf("namespace %s: %s: %w", namespace.Name, strings.Join(violations, "; "), ErrPolicyViolation))
+			continue
+		}
+		secret := kubernetesclaim.NewSecret(h.claim.Spec.Template.KubernetesDestination, namespace.Name, &h.claim, values)
+		result, err := kubernetesclaim.ApplySecret(h.ctx, h.client, secret, &h.claim)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("namespace %s: %w", namespace.Name, err))
+			continue
+		}
+		h.namespaces = append(h.namespaces, namespace.Name)
+		if result != claimhandlers.ResultUnchanged {
+			h.written = append(h.written, namespace.Name)
+		}
+	}
+
+	for _, secret := range owned {
+		if selectedSet[secret.Namespace] {
+			continue
+		}
+		if err := kubernetesclaim.DeleteSecret(h.ctx, h.client, secret); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (h *Handler) Cleanup() error {
+	owned, err := h.ownedSecrets()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, secret := range owned {
+		if err := kubernetesclaim.DeleteSecret(h.ctx, h.client, secret); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// policyViolations returns the violations of the template of every policy applying to namespace
+func (h *Handler) policyViolations(policies []v1alpha1.PasswordPolicy, namespace v1.Namespace) ([]string, error) {
+	var violations []string
+	for i := range policies {
+		applies, err := policies[i].AppliesTo(namespace.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if applies {
+			violations = append(violations, policies[i].PropertyViolations(h.claim.Spec.Template.Properties)...)
+		}
+	}
+	return violations, nil
+}
+
+// selectedNamespaces returns the active namespaces matching the namespaceSelector, sorted by name.
+func (h *Handler) selectedNamespaces() ([]v1.Namespace, error) {
+	selector := labels.Everything()
+	if h.claim.Spec.NamespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(h.claim.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, retry.Terminal(fmt.Errorf("invalid namespace selector: %w", err))
+		}
+	}
+
+	var namespaces v1.NamespaceList
+	if err := h.client.List(h.ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %w", err)
+	}
+	var active []v1.Namespace
+	for _, namespace := range namespaces.Items {
+		if namespace.Status.Phase == v1.NamespaceTerminating {
+			continue
+		}
+		active = append(active, namespace)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Name < active[j].Name })
+	return active, nil
+}
+
+// ownedSecrets returns the replicas written by the claim in any namespace, sorted by namespace.
+func (h *Handler) ownedSecrets() ([]v1.Secret, error) {
+	secrets, err := kubernetesclaim.OwnedSecrets(h.ctx, h.client, &h.claim)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []v1.Secret
+	for _, secret := range secrets {
+		if secret.Name == h.claim.Spec.Template.Name {
+			owned = append(owned, secret)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool { return owned[i].Namespace < owned[j].Namespace })
+	return owned, nil
+}
@@ -0,0 +1,76 @@
+package clusterclaim
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestHandleSkipsNamespacesViolatingTheirPolicy(t *testing.T) {
+	claim := v1alpha1.ClusterSecretClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", UID: "uid"},
+		Spec: v1alpha1.ClusterSecretClaimSpec{
+			Template: v1alpha1.KubernetesClaim{
+				KubernetesDestination: v1alpha1.KubernetesDestination{Name: "shared"},
+				Properties: []v1alpha1.SecretClaimProperty{{
+					Name: "password",
+					PropertySource: v1alpha1.PropertySource{PropertyGenerator: &v1alpha1.PropertyGenerator{
+						Password: &v1alpha1.PasswordGenerator{Length: 12},
+					}},
+				}},
+			},
+		},
+	}
+	c := newClient(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+		// prod started matching after the claim was admitted
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}},
+		&v1alpha1.PasswordPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod"},
+			Spec: v1alpha1.PasswordPolicySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				MinLength:         32,
+			},
+		},
+	)
+
+	handler := NewHandler(claim, context.Background(), c)
+	err := handler.Handle()
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("got error %v, want %v", err, ErrPolicyViolation)
+	}
+	if got, want := handler.Namespaces(), []string{"dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got namespaces %v, want %v", got, want)
+	}
+	var secret v1.Secret
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "dev", Name: "shared"}, &secret); err != nil {
+		t.Errorf("secret in dev: %v", err)
+	}
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "shared"}, &secret)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("got %v for the secret in prod, want not found", err)
+	}
+}
@@ -7,11 +7,44 @@ import (
 	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers"
 	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/kubernetesclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers/storeclaim"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateClaimHandler(claim v1alpha1.SecretClaim, ctx context.Context) (claimhandlers.ClaimHandler, error) {
+// CreateClaimHandlers returns a handler for every destination of the claim. The store
+// client is used by secretStore destinations and is nil if the claim has no store.
+func CreateClaimHandlers(claim v1alpha1.SecretClaim, ctx context.Context, c client.Client, store providers.Client) ([]claimhandlers.ClaimHandler, error) {
 	if claim.Spec.KubernetesClaim != nil {
-		return kubernetesclaim.NewHandler(claim, ctx), nil
+		return []claimhandlers.ClaimHandler{
+			kubernetesclaim.NewHandler(claim, claim.Spec.KubernetesClaim.KubernetesDestination, ctx, c),
+		}, nil
 	}
-	return nil, fmt.Errorf("unable to create claim handler - unable to determine claim type")
+
+	var handlers []claimhandlers.ClaimHandler
+	for i, destination := range claim.Spec.Destinations {
+		handler, err := createDestinationHandler(claim, destination, ctx, c, store)
+		if err != nil {
+			return nil, fmt.Errorf("destination %d: %w", i, err)
+		}
+		handlers = append(handlers, handler)
+	}
+	if len(handlers) == 0 {
+		return nil, retry.Terminal(fmt.Errorf("unable to create claim handler - unable to determine claim type"))
+	}
+	return handlers, nil
+}
+
+func createDestinationHandler(claim v1alpha1.SecretClaim, destination v1alpha1.SecretClaimDestination, ctx context.Context, c client.Client, store providers.Client) (claimhandlers.ClaimHandler, error) {
+	if destination.Kubernetes != nil {
+		return kubernetesclaim.NewHandler(claim, *destination.Kubernetes, ctx, c), nil
+	}
+	if destination.SecretStore != nil {
+		if store == nil {
+			return nil, retry.Terminal(fmt.Errorf("a secretStore destination requires spec.secretStoreRef"))
+		}
+		return storeclaim.NewHandler(claim, *destination.SecretStore, ctx, store), nil
+	}
+	return nil, retry.Terminal(fmt.Errorf("unable to create claim handler - unable to determine destination type"))
 }
@@ -1,5 +1,37 @@
 package claimhandlers
 
+import "time"
+
+// Result describes what Handle changed at a destination
+type Result string
+
+const (
+	// ResultUnchanged is a destination that already held the values
+	ResultUnchanged Result = ""
+	// ResultCreated is a destination that did not exist before
+	ResultCreated Result = "Created"
+	// ResultUpdated is a destination whose values were replaced
+	ResultUpdated Result = "Updated"
+	// ResultAdopted is an existing destination the claim now tracks as its own
+	ResultAdopted Result = "Adopted"
+)
+
+// ClaimHandler writes the resolved properties of a claim to a single destination
 type ClaimHandler interface {
-	Handle() error
+	// Destination identifies the destination in the claim status
+	Destination() string
+	// Values returns the property values the destination holds from an earlier Handle, or nil
+	// if it holds none, so generated values are kept rather than replaced on every reconcile
+	Values() (map[string][]byte, error)
+	// Handle writes the resolved property values to the destination
+	Handle(values map[string][]byte) (Result, error)
+	// Cleanup removes whatever Handle wrote for the claim
+	Cleanup() error
+}
+
+// CreationTimer is implemented by destinations that know when they were first written, so the
+// age of values kept from them is known after a restart
+type CreationTimer interface {
+	// CreationTime returns when the destination was created, or the zero time if it does not exist
+	CreationTime() (time.Time, error)
 }
@@ -3,116 +3,237 @@ package kubernetesclaim
 import (
 	"context"
 	"encoding/base64"
+	stderrors "errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers"
-	"github.com/secrets-operator/secrets-operator/pkg/clients/kube"
-	"github.com/secrets-operator/secrets-operator/pkg/source"
+	"github.com/secrets-operator/secrets-operator/pkg/grants"
+	"github.com/secrets-operator/secrets-operator/pkg/ownership"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ErrOwnershipConflict is returned when the destination Secret exists but was not written by the claim
+var ErrOwnershipConflict = stderrors.New("secret is not owned by the claim")
+
 type handler struct {
-	ctx   context.Context
-	claim v1alpha1.SecretClaim
+	ctx         context.Context
+	claim       v1alpha1.SecretClaim
+	destination v1alpha1.KubernetesDestination
+	client      client.Client
 }
 
-func (h handler) Handle() error {
-	kubernetesClaim := h.claim.Spec.KubernetesClaim
-	secretProperties := map[string][]byte{}
-	for _, property := range kubernetesClaim.Properties {
-		sourcedProperty, err := source.HandleProperty(property.PropertySource)
-		if err != nil {
-			return fmt.Errorf("error sourcing property %s", property.Name)
-		}
-		sourcePropertyBytes := []byte(sourcedProperty)
-		encodedSecret := make([]byte, base64.StdEncoding.EncodedLen(len(sourcePropertyBytes)))
-		base64.StdEncoding.Encode(encodedSecret, sourcePropertyBytes)
-		secretProperties[property.Name] = encodedSecret
+func (h handler) Destination() string {
+	return fmt.Sprintf("kubernetes:%s/%s", h.namespace(), h.destination.Name)
+}
+
+// Values reads the Secret written for the claim. Secrets the claim does not own are ignored.
+func (h handler) Values() (map[string][]byte, error) {
+	secret, err := h.ownedSecret()
+	if secret == nil || err != nil {
+		return nil, err
 	}
+	return SecretValues(*secret), nil
+}
 
-	secret := createSecret(h.claim, secretProperties)
-	err := applySecret(h.ctx, secret, h.claim)
-	if err != nil {
-		return fmt.Errorf("error when applying secret %w", err)
+// CreationTime returns the creation time of the Secret written for the claim
+func (h handler) CreationTime() (time.Time, error) {
+	secret, err := h.ownedSecret()
+	if secret == nil || err != nil {
+		return time.Time{}, err
 	}
+	return secret.CreationTimestamp.Time, nil
+}
 
-	return nil
+// ownedSecret returns the Secret written for the claim, or nil if there is none
+func (h handler) ownedSecret() (*v1.Secret, error) {
+	var secret v1.Secret
+	err := h.client.Get(h.ctx, types.NamespacedName{Namespace: h.namespace(), Name: h.destination.Name}, &secret)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting secret %s: %w", h.destination.Name, err)
+	}
+	if !ownership.IsOwnedBy(&secret, &h.claim) {
+		return nil, nil
+	}
+	return &secret, nil
 }
 
-func applySecret(ctx context.Context, secret v1.Secret, claim v1alpha1.SecretClaim) error {
+func (h handler) Handle(values map[string][]byte) (claimhandlers.Result, error) {
+	namespace := h.namespace()
+	err := grants.Authorize(h.ctx, h.client, "SecretClaim", h.claim.Namespace, namespace, h.destination.Name)
+	if err != nil {
+		return claimhandlers.ResultUnchanged, err
+	}
 
-	client, err := kube.CreateClientSet()
+	secret := NewSecret(h.destination, namespace, &h.claim, values)
+	result, err := ApplySecret(h.ctx, h.client, secret, &h.claim)
 	if err != nil {
-		return err
+		return claimhandlers.ResultUnchanged, fmt.Errorf("error when applying secret %w", err)
 	}
 
-	secretClient := client.CoreV1().Secrets(secret.Namespace)
-	existingSecret, err := secretClient.Get(ctx, secret.Name, metav1.GetOptions{})
+	return result, nil
+}
+
+// ApplySecret creates or updates the secret, refusing to overwrite a Secret the owner does not own.
+// A Secret owned through a legacy owner reference is adopted by adding the ownership labels.
+func ApplySecret(ctx context.Context, c client.Client, secret v1.Secret, owner metav1.Object) (claimhandlers.Result, error) {
+	var existingSecret v1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, &existingSecret)
 
 	if errors.IsNotFound(err) {
-		_, err := secretClient.Create(ctx, &secret, metav1.CreateOptions{})
+		err := c.Create(ctx, &secret)
 		if err != nil {
-			return fmt.Errorf("error creating secret %s: %w", secret.Name, err)
+			return claimhandlers.ResultUnchanged, fmt.Errorf("error creating secret %s: %w", secret.Name, err)
 		}
-	} else {
-		if !checkOwnership(claim, existingSecret.OwnerReferences) {
-			return fmt.Errorf("existing secret %s is not owned by this claim %s", secret.Name, claim.Name)
+		return claimhandlers.ResultCreated, nil
+	} else if err != nil {
+		return claimhandlers.ResultUnchanged, fmt.Errorf("error getting secret %s: %w", secret.Name, err)
+	}
+
+	if !ownership.IsOwnedBy(&existingSecret, owner) {
+		return claimhandlers.ResultUnchanged, fmt.Errorf("existing secret %s is not owned by this claim %s: %w", secret.Name, owner.GetName(), ErrOwnershipConflict)
+	}
+	result := claimhandlers.ResultUpdated
+	if !ownership.HasLabels(&existingSecret, owner) {
+		result = claimhandlers.ResultAdopted
+	} else if reflect.DeepEqual(existingSecret.Data, secret.Data) && existingSecret.Type == secret.Type &&
+		reflect.DeepEqual(existingSecret.Labels, secret.Labels) && reflect.DeepEqual(existingSecret.Annotations, secret.Annotations) {
+		return claimhandlers.ResultUnchanged, nil
+	}
+	secret.ResourceVersion = existingSecret.ResourceVersion
+	if err := c.Update(ctx, &secret); err != nil {
+		return claimhandlers.ResultUnchanged, fmt.Errorf("error updating secret %s: %w", secret.Name, err)
+	}
+	return result, nil
+}
+
+func (h handler) Cleanup() error {
+	var existingSecret v1.Secret
+	err := h.client.Get(h.ctx, types.NamespacedName{Namespace: h.namespace(), Name: h.destination.Name}, &existingSecret)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error getting secret %s: %w", h.destination.Name, err)
+	}
+	if !ownership.IsOwnedBy(&existingSecret, &h.claim) {
+		return nil
+	}
+	return DeleteSecret(h.ctx, h.client, existingSecret)
+}
+
+// PruneSecrets deletes the Secrets labelled as owned by owner that are not in keep,
+// e.g. after a destination was removed from a claim.
+func PruneSecrets(ctx context.Context, c client.Client, owner metav1.Object, keep map[types.NamespacedName]bool) error {
+	secrets, err := OwnedSecrets(ctx, c, owner)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if keep[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] {
+			continue
 		}
-		_, err := secretClient.Update(ctx, &secret, metav1.UpdateOptions{})
-		if err != nil {
-			return fmt.Errorf("error updating secret %s: %w", secret.Name, err)
+		if err := DeleteSecret(ctx, c, secret); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func createSecret(claim v1alpha1.SecretClaim, secretProperties map[string][]byte) v1.Secret {
-	kubernetesClaim := claim.Spec.KubernetesClaim
-	ownerRef := createOwnerReference(claim)
+// OwnedSecrets returns the Secrets in any namespace labelled as owned by owner.
+func OwnedSecrets(ctx context.Context, c client.Reader, owner metav1.Object) ([]v1.Secret, error) {
+	var secrets v1.SecretList
+	if err := c.List(ctx, &secrets, client.MatchingLabels(ownership.Labels(owner))); err != nil {
+		return nil, fmt.Errorf("error listing secrets of %s: %w", owner.GetName(), err)
+	}
+	return secrets.Items, nil
+}
+
+// DeleteSecret deletes the secret, ignoring Secrets that are already gone.
+func DeleteSecret(ctx context.Context, c client.Client, secret v1.Secret) error {
+	err := c.Delete(ctx, &secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("error deleting secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// NewSecret builds the Secret described by the destination in namespace, labelled as owned by owner.
+func NewSecret(destination v1alpha1.KubernetesDestination, namespace string, owner metav1.Object, values map[string][]byte) v1.Secret {
 	return v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            kubernetesClaim.Name,
-			Namespace:       kubernetesClaim.Namespace,
-			Labels:          kubernetesClaim.Labels,
-			Annotations:     kubernetesClaim.Annotations,
-			OwnerReferences: []metav1.OwnerReference{ownerRef},
+			Name:        destination.Name,
+			Namespace:   namespace,
+			Labels:      ownership.WithLabels(destination.Labels, owner),
+			Annotations: destination.Annotations,
 		},
-		Data: secretProperties,
-		Type: kubernetesClaim.SecretType,
+		Data: encodeValues(values),
+		Type: destination.SecretType,
 	}
 }
 
-func NewHandler(claim v1alpha1.SecretClaim, ctx context.Context) claimhandlers.ClaimHandler {
-	return &handler{ctx: ctx, claim: claim}
+// encodeValues base64 encodes the values written to the Secret's data.
+func encodeValues(values map[string][]byte) map[string][]byte {
+	data := map[string][]byte{}
+	for name, value := range values {
+		encoded := make([]byte, base64.StdEncoding.EncodedLen(len(value)))
+		base64.StdEncoding.Encode(encoded, value)
+		data[name] = encoded
+	}
+	return data
 }
 
-func createOwnerReference(claim v1alpha1.SecretClaim) metav1.OwnerReference {
-	return metav1.OwnerReference{
-		APIVersion: claim.TypeMeta.APIVersion,
-		Kind:       claim.TypeMeta.Kind,
-		Name:       claim.ObjectMeta.Name,
-		UID:        claim.UID,
+// SecretValues returns the property values held by a Secret written by NewSecret.
+func SecretValues(secret v1.Secret) map[string][]byte {
+	values := map[string][]byte{}
+	for name, encoded := range secret.Data {
+		value := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+		n, err := base64.StdEncoding.Decode(value, encoded)
+		if err != nil {
+			continue
+		}
+		values[name] = value[:n]
 	}
+	return values
 }
 
-func checkOwnership(claim v1alpha1.SecretClaim, ownerRefs []metav1.OwnerReference) bool {
-	for _, ownerRef := range ownerRefs {
-		if ownerRef.Kind != claim.Kind {
-			return false
-		}
-		if ownerRef.APIVersion != claim.APIVersion {
-			return false
-		}
-		if ownerRef.Name != claim.Name {
-			return false
-		}
-		if ownerRef.UID != claim.UID {
-			return false
+// namespace returns the namespace the Secret is written to, defaulting to the claim's own.
+func (h handler) namespace() string {
+	if h.destination.Namespace != "" {
+		return h.destination.Namespace
+	}
+	return h.claim.Namespace
+}
+
+// Targets returns the Secrets the kubernetes destinations of the claim are written to.
+func Targets(claim v1alpha1.SecretClaim) map[types.NamespacedName]bool {
+	var destinations []v1alpha1.KubernetesDestination
+	if claim.Spec.KubernetesClaim != nil {
+		destinations = append(destinations, claim.Spec.KubernetesClaim.KubernetesDestination)
+	}
+	for _, destination := range claim.Spec.Destinations {
+		if destination.Kubernetes != nil {
+			destinations = append(destinations, *destination.Kubernetes)
 		}
-		return true
 	}
-	return false
+
+	targets := map[types.NamespacedName]bool{}
+	for _, destination := range destinations {
+		h := handler{claim: claim, destination: destination}
+		targets[types.NamespacedName{Namespace: h.namespace(), Name: destination.Name}] = true
+	}
+	return targets
+}
+
+var _ claimhandlers.CreationTimer = &handler{}
+
+func NewHandler(claim v1alpha1.SecretClaim, destination v1alpha1.KubernetesDestination, ctx context.Context, c client.Client) claimhandlers.ClaimHandler {
+	return &handler{ctx: ctx, claim: claim, destination: destination, client: c}
 }
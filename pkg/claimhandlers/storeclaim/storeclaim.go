@@ -0,0 +1,95 @@
+package storeclaim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/claimhandlers"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// handler writes every property of a claim to the claim's secret store
+type handler struct {
+	ctx         context.Context
+	claim       v1alpha1.SecretClaim
+	destination v1alpha1.StoreDestination
+	client      providers.Client
+}
+
+func (h handler) Destination() string {
+	return fmt.Sprintf("secretstore:%s/%s", h.claim.Spec.SecretStoreRef.Name, h.destination.NamePrefix)
+}
+
+// Values reads the latest version of the store secret of every property written before
+func (h handler) Values() (map[string][]byte, error) {
+	values := map[string][]byte{}
+	for _, property := range h.claim.ClaimProperties() {
+		value, err := h.client.GetSecret(h.ctx, h.secretName(property.Name), "")
+		if errors.Is(err, providers.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading secret %s: %w", h.secretName(property.Name), providers.ProviderError(err))
+		}
+		values[property.Name] = value
+	}
+	return values, nil
+}
+
+// Handle writes the values that differ from the latest version in the store, each write adds a
+// version to stores that keep them
+func (h handler) Handle(values map[string][]byte) (claimhandlers.Result, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	current, err := h.Values()
+	if err != nil {
+		return claimhandlers.ResultUnchanged, err
+	}
+	result := claimhandlers.ResultUnchanged
+	var errs []error
+	for _, name := range names {
+		if value, ok := current[name]; ok && bytes.Equal(value, values[name]) {
+			continue
+		}
+		if err := h.client.PutSecret(h.ctx, h.secretName(name), values[name]); err != nil {
+			errs = append(errs, fmt.Errorf("error writing secret %s: %w", h.secretName(name), providers.ProviderError(err)))
+			continue
+		}
+		result = claimhandlers.ResultUpdated
+	}
+	if len(errs) > 0 {
+		return claimhandlers.ResultUnchanged, utilerrors.NewAggregate(errs)
+	}
+	return result, nil
+}
+
+// Cleanup deletes the store secrets of the claim's properties, unless the destination retains them.
+func (h handler) Cleanup() error {
+	if h.destination.DeletionPolicy != v1alpha1.DeletionPolicyDelete {
+		return nil
+	}
+	var errs []error
+	for _, property := range h.claim.ClaimProperties() {
+		err := h.client.DeleteSecret(h.ctx, h.secretName(property.Name))
+		if err != nil && !errors.Is(err, providers.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("error deleting secret %s: %w", h.secretName(property.Name), providers.ProviderError(err)))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (h handler) secretName(property string) string {
+	return h.destination.NamePrefix + property
+}
+
+func NewHandler(claim v1alpha1.SecretClaim, destination v1alpha1.StoreDestination, ctx context.Context, client providers.Client) claimhandlers.ClaimHandler {
+	return &handler{ctx: ctx, claim: claim, destination: destination, client: client}
+}
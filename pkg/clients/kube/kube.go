@@ -1,91 +1,91 @@
 package kube
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
 
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/tools/clientcmd/api"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
-const (
-	DefaultKubeConfigFile = "config"
-	DefaultKubeConfigPath = ".kube"
-)
-
-func CreateClientSet() (kubernetes.Interface, error) {
-	config, err := createKubeConfig(DefaultKubeConfigPath, DefaultKubeConfigFile)
+// ConfigFromKubeconfig returns the rest.Config of the current context of a kubeconfig. The config
+// is built only from the inline server, certificate and token fields: kubeconfigs come from store
+// specs, so fields that read files, run commands or call auth providers in the process loading
+// them are rejected, as are contexts without credentials.
+func ConfigFromKubeconfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
 	}
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, err
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("invalid kubeconfig: current context %q is not defined", config.CurrentContext)
 	}
-	return clientset, nil
-}
-
-func createKubeConfig(kubeConfigPath, kubeConfigFile string) (*rest.Config, error) {
-	masterURL := ""
-	kubeConfigEnv := os.Getenv("KUBECONFIG")
-	if kubeConfigEnv != "" {
-		pathList := filepath.SplitList(kubeConfigEnv)
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{Precedence: pathList},
-			&clientcmd.ConfigOverrides{ClusterInfo: api.Cluster{Server: masterURL}}).ClientConfig()
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("invalid kubeconfig: cluster %q is not defined", kubeContext.Cluster)
 	}
-	kubeconfig := createKubeConfigPath(kubeConfigPath, kubeConfigFile)
-	var config *rest.Config
-	var err error
-	if kubeconfig != nil {
-		exists, err := fileExists(*kubeconfig)
-		if err == nil && exists {
-			// use the current context in kubeconfig
-			config, err = clientcmd.BuildConfigFromFlags(masterURL, *kubeconfig)
-			if err != nil {
-				return nil, err
-			}
-		}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("invalid kubeconfig: user %q is not defined", kubeContext.AuthInfo)
 	}
-	if config == nil {
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, err
-		}
+	if unsupported := unsupportedFields(cluster, authInfo); len(unsupported) > 0 {
+		return nil, fmt.Errorf("invalid kubeconfig: %s not supported, use the inline data fields", strings.Join(unsupported, ", "))
 	}
-
-	return config, nil
-}
-
-func createKubeConfigPath(kubeConfigPath, kubeConfigFile string) *string {
-	path := ""
-	if home := homeDir(); home != "" {
-		path = filepath.Join(home, kubeConfigPath, kubeConfigFile)
+	if cluster.Server == "" {
+		return nil, errors.New("invalid kubeconfig: cluster server is not set")
+	}
+	if authInfo.Token == "" && len(authInfo.ClientCertificateData) == 0 {
+		return nil, errors.New("invalid kubeconfig: user has neither a token nor a client certificate")
 	}
-	return &path
+	return &rest.Config{
+		Host:        cluster.Server,
+		BearerToken: authInfo.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure:   cluster.InsecureSkipTLSVerify,
+			ServerName: cluster.TLSServerName,
+			CAData:     cluster.CertificateAuthorityData,
+			CertData:   authInfo.ClientCertificateData,
+			KeyData:    authInfo.ClientKeyData,
+		},
+	}, nil
 }
 
-func fileExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		return true, nil
-	}
-	if os.IsNotExist(err) {
-		return false, nil
+// unsupportedFields returns the kubeconfig fields of cluster and authInfo that are set and are not
+// inline data
+func unsupportedFields(cluster *clientcmdapi.Cluster, authInfo *clientcmdapi.AuthInfo) []string {
+	var fields []string
+	set := func(name string, isSet bool) {
+		if isSet {
+			fields = append(fields, name)
+		}
 	}
-	return true, fmt.Errorf("failed to check if file exists %s: %w", path, err)
+	set("certificate-authority", cluster.CertificateAuthority != "")
+	set("proxy-url", cluster.ProxyURL != "")
+	set("client-certificate", authInfo.ClientCertificate != "")
+	set("client-key", authInfo.ClientKey != "")
+	set("tokenFile", authInfo.TokenFile != "")
+	set("as", authInfo.Impersonate != "")
+	set("as-groups", len(authInfo.ImpersonateGroups) > 0)
+	set("as-user-extra", len(authInfo.ImpersonateUserExtra) > 0)
+	set("username", authInfo.Username != "")
+	set("password", authInfo.Password != "")
+	set("auth-provider", authInfo.AuthProvider != nil)
+	set("exec", authInfo.Exec != nil)
+	return fields
 }
 
-func homeDir() string {
-	if h := os.Getenv("HOME"); h != "" {
-		return h
-	}
-	h := os.Getenv("USERPROFILE") // windows
-	if h == "" {
-		h = "."
+// ConfigFromToken returns the rest.Config of the API server at server, authenticated with a
+// service account token and trusting the certificates of caBundle
+func ConfigFromToken(server, token string, caBundle []byte) (*rest.Config, error) {
+	if server == "" {
+		return nil, errors.New("api server address is not set")
 	}
-	return h
+	return &rest.Config{
+		Host:            server,
+		BearerToken:     token,
+		TLSClientConfig: rest.TLSClientConfig{CAData: caBundle},
+	}, nil
 }
@@ -0,0 +1,148 @@
+package kube
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+)
+
+// writeKubeconfig returns a kubeconfig connecting with a token, changed by modify. It is written
+// as JSON, which kubeconfigs accept like YAML.
+func writeKubeconfig(t *testing.T, modify func(cluster *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo)) []byte {
+	t.Helper()
+	cluster := clientcmdv1.Cluster{Server: "https://remote.example.com:6443", CertificateAuthorityData: []byte("ca")}
+	authInfo := clientcmdv1.AuthInfo{Token: "token"}
+	modify(&cluster, &authInfo)
+	data, err := json.Marshal(clientcmdv1.Config{
+		Kind:           "Config",
+		APIVersion:     "v1",
+		Clusters:       []clientcmdv1.NamedCluster{{Name: "remote", Cluster: cluster}},
+		AuthInfos:      []clientcmdv1.NamedAuthInfo{{Name: "remote", AuthInfo: authInfo}},
+		Contexts:       []clientcmdv1.NamedContext{{Name: "remote", Context: clientcmdv1.Context{Cluster: "remote", AuthInfo: "remote"}}},
+		CurrentContext: "remote",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestConfigFromKubeconfig(t *testing.T) {
+	config, err := ConfigFromKubeconfig(writeKubeconfig(t, func(cluster *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+		authInfo.ClientCertificateData = []byte("cert")
+		authInfo.ClientKeyData = []byte("key")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "https://remote.example.com:6443" || config.BearerToken != "token" ||
+		string(config.CAData) != "ca" || string(config.CertData) != "cert" || string(config.KeyData) != "key" {
+		t.Errorf("got %+v, want the inline fields of the kubeconfig", config)
+	}
+}
+
+func TestConfigFromKubeconfigRejectsFieldsUsingTheProcess(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cluster *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo)
+		wantErr string
+	}{
+		{
+			name: "exec",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.Exec = &clientcmdv1.ExecConfig{Command: "/bin/sh", APIVersion: "client.authentication.k8s.io/v1beta1"}
+			},
+			wantErr: "exec",
+		},
+		{
+			name: "auth provider",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.AuthProvider = &clientcmdv1.AuthProviderConfig{Name: "gcp"}
+			},
+			wantErr: "auth-provider",
+		},
+		{
+			name: "token file",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.Token = ""
+				authInfo.TokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+			},
+			wantErr: "tokenFile",
+		},
+		{
+			name: "client certificate file",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.ClientCertificate = "/etc/operator/tls.crt"
+			},
+			wantErr: "client-certificate",
+		},
+		{
+			name: "client key file",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.ClientKey = "/etc/operator/tls.key"
+			},
+			wantErr: "client-key",
+		},
+		{
+			name: "certificate authority file",
+			modify: func(cluster *clientcmdv1.Cluster, _ *clientcmdv1.AuthInfo) {
+				cluster.CertificateAuthority = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+			},
+			wantErr: "certificate-authority",
+		},
+		{
+			name: "proxy",
+			modify: func(cluster *clientcmdv1.Cluster, _ *clientcmdv1.AuthInfo) {
+				cluster.ProxyURL = "http://proxy.internal:3128"
+			},
+			wantErr: "proxy-url",
+		},
+		{
+			name: "impersonation",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.Impersonate = "system:admin"
+				authInfo.ImpersonateGroups = []string{"system:masters"}
+			},
+			wantErr: "as, as-groups",
+		},
+		{
+			name: "basic auth",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.Username = "admin"
+				authInfo.Password = "admin"
+			},
+			wantErr: "username, password",
+		},
+		{
+			name: "no server",
+			modify: func(cluster *clientcmdv1.Cluster, _ *clientcmdv1.AuthInfo) {
+				cluster.Server = ""
+			},
+			wantErr: "server is not set",
+		},
+		{
+			name: "no credentials",
+			modify: func(_ *clientcmdv1.Cluster, authInfo *clientcmdv1.AuthInfo) {
+				authInfo.Token = ""
+			},
+			wantErr: "neither a token nor a client certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConfigFromKubeconfig(writeKubeconfig(t, tt.modify))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigFromKubeconfigRequiresTheCurrentContext(t *testing.T) {
+	data := []byte(`{"kind": "Config", "apiVersion": "v1", "current-context": "missing"}`)
+	if _, err := ConfigFromKubeconfig(data); err == nil {
+		t.Error("got no error for a kubeconfig without its current context")
+	}
+}
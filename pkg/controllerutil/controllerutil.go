@@ -1,7 +1,9 @@
 package controllerutil
 
 import (
+	"errors"
 	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -9,6 +11,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
+// ErrOwnershipConflict is returned when an object the operator would write exists but is not
+// controlled by the object it would be written for
+var ErrOwnershipConflict = errors.New("object is not controlled by the operator")
+
+// CheckControlledBy returns an ErrOwnershipConflict error unless the existing object is controlled by owner
+func CheckControlledBy(existing, owner metav1.Object) error {
+	if metav1.IsControlledBy(existing, owner) {
+		return nil
+	}
+	return fmt.Errorf("%s/%s exists and is not controlled by %s: %w",
+		existing.GetNamespace(), existing.GetName(), owner.GetName(), ErrOwnershipConflict)
+}
+
 func SetControllerReference(owner metav1.Object, controlled metav1.Object, scheme *runtime.Scheme) error {
 	// Validate the owner.
 	ro, ok := owner.(runtime.Object)
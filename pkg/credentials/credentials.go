@@ -0,0 +1,34 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretResolver resolves inline values, and secretRef values from the referenced Secret
+func SecretResolver(reader client.Reader) providers.ValueResolver {
+	return func(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error) {
+		if v.Value != nil {
+			return *v.Value, nil
+		}
+		if v.SecretRef == nil {
+			return "", fmt.Errorf("neither value nor secretRef is set")
+		}
+		ref := v.SecretRef
+		var secret corev1.Secret
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return "", fmt.Errorf("unable to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+		return string(value), nil
+	}
+}
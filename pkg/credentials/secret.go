@@ -0,0 +1,162 @@
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HashAnnotation on the store pod template changes with the credentials, so the store
+// agent restarts when a referenced secret is updated
+const HashAnnotation = "secret-operator.io/credentials-hash"
+
+// SecretName returns the name of the secret holding the credentials of the store deployment
+func SecretName(deployment string) string {
+	return deployment + "-store-agent-credentials"
+}
+
+// legacySecretName is the name SecretName returned before, which is easily taken by a Secret of
+// the user, e.g. aws-credentials for a store named aws
+func legacySecretName(deployment string) string {
+	return deployment + "-credentials"
+}
+
+// AgentProvider returns the provider of the store as passed to its agent, the deployment name in
+// namespace. Inline values are replaced by secretRefs to the credentials secret written by
+// ReconcileSecret, so they are not copied into the Deployment.
+func AgentProvider(store v1alpha1.GenericStore, name, namespace string) v1alpha1.Provider {
+	provider := *store.GetSpec().Provider.DeepCopy()
+	v1alpha1.ReplaceInlineValues(&provider, inlineRef(name, namespace))
+	return provider
+}
+
+// inlineRef returns the secretRef standing in for the inline value at a field path of the
+// provider, a key of the credentials secret of the deployment name in namespace
+func inlineRef(name, namespace string) func(fldPath *field.Path) v1alpha1.SecretRef {
+	return func(fldPath *field.Path) v1alpha1.SecretRef {
+		return v1alpha1.SecretRef{Namespace: namespace, Name: SecretName(name), Key: fldPath.String()}
+	}
+}
+
+// EnvVars returns the environment of the store agent for the secretRef credentials of the
+// provider returned by AgentProvider. The values are read from the credentials secret of the
+// deployment name.
+func EnvVars(provider *v1alpha1.Provider, name string) []corev1.EnvVar {
+	var vars []corev1.EnvVar
+	for _, ref := range v1alpha1.SecretRefs(provider) {
+		env := storeagent.CredentialEnv(ref)
+		vars = append(vars, corev1.EnvVar{
+			Name: env,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: SecretName(name)},
+					Key:                  env,
+				},
+			},
+		})
+	}
+	return vars
+}
+
+// ReconcileSecret copies the credentials of the store into the credentials secret of the
+// deployment name in namespace: the values of secretRefs, which may live in another namespace
+// than the deployment that env.valueFrom cannot read across, and the inline values. It returns
+// a hash of the credentials, or an empty string when the store has none. A secret of that name
+// not controlled by the store is not overwritten, controllerutil.ErrOwnershipConflict is returned.
+func ReconcileSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, store v1alpha1.GenericStore, name, namespace string) (string, error) {
+	provider := *store.GetSpec().Provider.DeepCopy()
+	inline := v1alpha1.ReplaceInlineValues(&provider, inlineRef(name, namespace))
+	refs := v1alpha1.SecretRefs(&provider)
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName(name), Namespace: namespace},
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	var existing corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secret.Name}, &existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	exists := err == nil
+	if err := removeLegacySecret(ctx, c, store, name, namespace); err != nil {
+		return "", err
+	}
+	if len(refs) == 0 {
+		if exists && metav1.IsControlledBy(&existing, store) {
+			return "", client.IgnoreNotFound(c.Delete(ctx, &existing))
+		}
+		return "", nil
+	}
+	if exists {
+		if err := controllerutil.CheckControlledBy(&existing, store); err != nil {
+			return "", fmt.Errorf("credentials secret: %w", err)
+		}
+	}
+
+	resolve := SecretResolver(c)
+	secret.Data = map[string][]byte{}
+	for _, ref := range refs {
+		value, ok := inline[ref]
+		if !ok {
+			if value, err = resolve(ctx, v1alpha1.ValueOrSecretKey{SecretRef: &ref}); err != nil {
+				return "", err
+			}
+		}
+		secret.Data[storeagent.CredentialEnv(ref)] = []byte(value)
+	}
+	if err := controllerutil.SetControllerReference(store, &secret, scheme); err != nil {
+		return "", err
+	}
+	if exists {
+		secret.ResourceVersion = existing.ResourceVersion
+		err = c.Update(ctx, &secret)
+	} else {
+		err = c.Create(ctx, &secret)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write secret %s/%s: %w", namespace, secret.Name, err)
+	}
+	return hash(secret.Data), nil
+}
+
+// removeLegacySecret deletes the credentials secret the store wrote under its legacy name
+func removeLegacySecret(ctx context.Context, c client.Client, store v1alpha1.GenericStore, name, namespace string) error {
+	var legacy corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: legacySecretName(name)}, &legacy)
+	if apierrors.IsNotFound(err) || (err == nil && !metav1.IsControlledBy(&legacy, store)) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %w", namespace, legacySecretName(name), err)
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, &legacy))
+}
+
+func hash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(data[key])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
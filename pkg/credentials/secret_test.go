@@ -0,0 +1,78 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSecret(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	token := "s3cr3t"
+	store := &v1alpha1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "tenant", UID: "store-uid"},
+		Spec: v1alpha1.SecretStoreSpec{Provider: v1alpha1.Provider{Vault: &v1alpha1.VaultProvider{
+			Server: "https://vault.vault.svc:8200",
+			Auth:   v1alpha1.VaultAuth{Token: &v1alpha1.VaultTokenAuth{Token: v1alpha1.ValueOrSecretKey{Value: &token}}},
+		}}},
+	}
+	legacy := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: legacySecretName("vault"), Namespace: "tenant"}}
+	if err := controllerutil.SetControllerReference(store, legacy, scheme); err != nil {
+		t.Fatal(err)
+	}
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName("user"), Namespace: "tenant"},
+		Data:       map[string][]byte{"password": []byte("user password")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(store, legacy, userSecret).Build()
+
+	hash, err := ReconcileSecret(ctx, c, scheme, store, "vault", "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" {
+		t.Error("got no hash of the credentials")
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant", Name: SecretName("vault")}, &secret); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(&secret, store) {
+		t.Error("the credentials secret is not controlled by the store")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(legacy), &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("got %v for the secret of the legacy name, want not found", err)
+	}
+
+	// A Secret of the same name written by someone else is left alone
+	_, err = ReconcileSecret(ctx, c, scheme, store, "user", "tenant")
+	if !errors.Is(err, controllerutil.ErrOwnershipConflict) {
+		t.Fatalf("got error %v, want %v", err, controllerutil.ErrOwnershipConflict)
+	}
+	var current corev1.Secret
+	if err := c.Get(ctx, client.ObjectKeyFromObject(userSecret), &current); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(current.Data, userSecret.Data) || len(current.OwnerReferences) > 0 {
+		t.Errorf("the user's secret was changed: %+v", current)
+	}
+}
@@ -2,23 +2,28 @@ package deployment
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
-	secretoperatorv1alpha1 "github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
 	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
-	"github.com/secrets-operator/secrets-operator/pkg/secretstores/gcp"
+	"github.com/secrets-operator/secrets-operator/pkg/credentials"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	StoreOperatorImage = "mellardc/storeoperator:1.0"
+	// StoreAgentImage is the default store image, see the --store-image flag
+	StoreAgentImage = "store-agent:latest"
 )
 
 // Params to specify a Deployment specification.
@@ -51,57 +56,113 @@ func New(params Params) appsv1.Deployment {
 	}
 }
 
-func DeploymentParams(store v1alpha1.SecretStore) Params {
-	podSpec := newPodTemplateSpec(store)
+// DeploymentParams returns the params of the store deployment for the given store in namespace.
+// Namespaced stores are deployed next to the store, cluster stores into the operator namespace.
+// The image is used unless the store's deployment overrides set one. credentialsHash is the hash
+// returned by credentials.ReconcileSecret.
+func DeploymentParams(store v1alpha1.GenericStore, namespace string, image string, credentialsHash string) (Params, error) {
+	name := Name(store)
+	podSpec, err := newPodTemplateSpec(store, name, namespace, image, credentialsHash)
+	if err != nil {
+		return Params{}, err
+	}
+	replicas := int32(1)
+	if overrides := store.GetSpec().Deployment; overrides != nil && overrides.Replicas != nil {
+		replicas = *overrides.Replicas
+	}
 	return Params{
-		Name:            store.Name,
-		Namespace:       store.Namespace,
-		Selector:        NewLabels(store.Name),
+		Name:            name,
+		Namespace:       namespace,
+		Selector:        NewLabels(name),
 		PodTemplateSpec: podSpec,
-		Replicas:        1,
-	}
+		Replicas:        replicas,
+	}, nil
 }
 
-func newPodTemplateSpec(store v1alpha1.SecretStore) corev1.PodTemplateSpec {
-	builder := builders.NewPodTemplateBuilder().
-		WithImage(StoreOperatorImage).
-		WithLabels(NewLabels(store.Name))
-
-	if store.Spec.Provider.GcpSecretsManager != nil {
-		return gcp.GcpPodTemplateSpec(store, builder)
+// Name returns the name of the store deployment. Deployments of cluster stores are prefixed
+// so they cannot clash with a namespaced store of the same name in the operator namespace.
+func Name(store v1alpha1.GenericStore) string {
+	if _, ok := store.(*v1alpha1.ClusterSecretStore); ok {
+		return "cluster-" + store.GetName()
 	}
-
-	return builder.PodTemplate
+	return store.GetName()
 }
 
-func Reconcile(client kubernetes.Interface, deployment appsv1.Deployment, owner client.Object) error {
-
-	err := secretoperatorv1alpha1.AddToScheme(scheme.Scheme)
+func newPodTemplateSpec(store v1alpha1.GenericStore, name, namespace string, image string, credentialsHash string) (corev1.PodTemplateSpec, error) {
+	provider, err := providers.ForSpec(&store.GetSpec().Provider)
 	if err != nil {
-		return err
+		return corev1.PodTemplateSpec{}, err
+	}
+	// Credentials reach the agent through the credentials secret, never the pod spec
+	agentProvider := credentials.AgentProvider(store, name, namespace)
+	providerJSON, err := json.Marshal(agentProvider)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
 	}
+	builder := builders.NewPodTemplateBuilder().
+		WithDeploymentOverrides(store.GetSpec().Deployment).
+		WithImage(image).
+		WithLabels(NewLabels(name)).
+		WithEnv(corev1.EnvVar{Name: storeagent.ProviderEnv, Value: string(providerJSON)}).
+		WithEnv(credentials.EnvVars(&agentProvider, name)...).
+		WithPorts(
+			corev1.ContainerPort{Name: "https", ContainerPort: storeagent.Port, Protocol: corev1.ProtocolTCP},
+			corev1.ContainerPort{Name: "health", ContainerPort: storeagent.HealthPort, Protocol: corev1.ProtocolTCP},
+		).
+		WithReadinessProbe(&corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("health")},
+			},
+		}).
+		WithVolume(corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: certificates.ServingSecretName(name)},
+			},
+		}, corev1.VolumeMount{Name: "tls", MountPath: storeagent.TLSDir, ReadOnly: true})
 
-	if err := controllerutil.SetControllerReference(owner, &deployment, scheme.Scheme); err != nil {
-		return err
+	if credentialsHash != "" {
+		builder.WithAnnotations(map[string]string{credentials.HashAnnotation: credentialsHash}).
+			WithVolume(corev1.Volume{
+				Name: "credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: credentials.SecretName(name)},
+				},
+			}, corev1.VolumeMount{Name: "credentials", MountPath: storeagent.CredentialsDir, ReadOnly: true})
 	}
 
-	create := func() error {
-		_, err := client.AppsV1().Deployments(deployment.Namespace).Create(context.Background(), &deployment, metav1.CreateOptions{})
+	provider.PodTemplate(store, builder)
+	return builder.PodTemplate, nil
+}
+
+// Reconcile creates the deployment or updates the existing one to match it.
+func Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, deployment appsv1.Deployment, owner client.Object) error {
+	if err := controllerutil.SetControllerReference(owner, &deployment, scheme); err != nil {
 		return err
 	}
+
 	// First check if deployment exists
-	_, err = client.AppsV1().Deployments(deployment.Namespace).Get(context.Background(), deployment.Name, metav1.GetOptions{})
+	var existing appsv1.Deployment
+	err := c.Get(ctx, types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}, &existing)
 	if err != nil && apierrors.IsNotFound(err) {
-		return create()
+		return c.Create(ctx, &deployment)
 	} else if err != nil {
 		return fmt.Errorf("failed to get deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
 	}
 
-	_, err = client.AppsV1().Deployments(deployment.Namespace).Update(context.Background(), &deployment, metav1.UpdateOptions{})
-	return err
+	deployment.ResourceVersion = existing.ResourceVersion
+	return c.Update(ctx, &deployment)
+}
+
+// Namespace returns the namespace the deployment of the store runs in
+func Namespace(store v1alpha1.GenericStore, operatorNamespace string) string {
+	if _, ok := store.(*v1alpha1.ClusterSecretStore); ok {
+		return operatorNamespace
+	}
+	return store.GetNamespace()
 }
 
-// NewLabels constructs a new set of labels for a Kibana pod
+// NewLabels constructs a new set of labels for a store agent pod
 func NewLabels(storeName string) map[string]string {
 	return map[string]string{"secret-operator.io/store-operator": storeName}
 }
@@ -1,24 +1,47 @@
 package generation
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/pkg/generation/generators/hmac"
 	"github.com/secrets-operator/secrets-operator/pkg/generation/generators/password"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func Generate(propertyGenerator v1alpha1.PropertyGenerator) (string, error) {
+// GeneratorType names the generator configured in propertyGenerator, e.g. for metrics
+func GeneratorType(propertyGenerator v1alpha1.PropertyGenerator) string {
+	if propertyGenerator.Hmac {
+		return "hmac"
+	} else if propertyGenerator.Password != nil {
+		return "password"
+	}
+	return "unknown"
+}
+
+// Generate returns a new value from the generator configured in propertyGenerator
+func Generate(ctx context.Context, propertyGenerator v1alpha1.PropertyGenerator) (value string, err error) {
+	_, span := tracing.Start(ctx, "generation.Generate", attribute.String("generator", GeneratorType(propertyGenerator)))
+	defer func() { tracing.End(span, err) }()
 	if propertyGenerator.Hmac {
 		return hmac.Hmac()
 	} else if propertyGenerator.Password != nil {
-		return password.GeneratePassword(
-			propertyGenerator.Password.Length,
-			propertyGenerator.Password.AllowedSymbols,
-			propertyGenerator.Password.NumDigits,
-			propertyGenerator.Password.NumSymbols,
-			propertyGenerator.Password.AllowRepeat,
-			propertyGenerator.Password.NoUpper)
+		// Defaults are normally applied at admission, but claims created while the
+		// webhook was disabled may still have unset fields.
+		passwordGenerator := propertyGenerator.Password.DeepCopy()
+		v1alpha1.SetPasswordGeneratorDefaults(passwordGenerator)
+		generated, err := password.GeneratePassword(
+			passwordGenerator.Length,
+			passwordGenerator.AllowedSymbols,
+			*passwordGenerator.NumDigits,
+			*passwordGenerator.NumSymbols,
+			passwordGenerator.AllowRepeat,
+			passwordGenerator.NoUpper)
+		// The generator only fails for settings it cannot satisfy, e.g. more digits than the length.
+		return generated, retry.Terminal(err)
 	}
-	return "", fmt.Errorf("unable to determine property generator")
+	return "", retry.Terminal(fmt.Errorf("unable to determine property generator"))
 }
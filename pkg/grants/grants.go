@@ -0,0 +1,30 @@
+package grants
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Authorize returns an error unless a claim of kind in fromNamespace may write the named Secret
+// into toNamespace. Writes within the claim's own namespace are always allowed, anything else
+// requires a SecretGrant in the target namespace.
+func Authorize(ctx context.Context, c client.Reader, kind, fromNamespace, toNamespace, secretName string) error {
+	if fromNamespace == toNamespace {
+		return nil
+	}
+
+	var grants v1alpha1.SecretGrantList
+	if err := c.List(ctx, &grants, client.InNamespace(toNamespace)); err != nil {
+		return fmt.Errorf("unable to list secret grants in %s: %w", toNamespace, err)
+	}
+	for _, grant := range grants.Items {
+		if grant.Allows(kind, fromNamespace, secretName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no SecretGrant in namespace %s allows %s from namespace %s to write secret %s",
+		toNamespace, kind, fromNamespace, secretName)
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "secret_operator"
+
+// Reasons a claim failed to sync, the reason label of ClaimSyncErrors
+const (
+	// ReasonStore is a claim whose secret store could not be reached
+	ReasonStore = "store"
+	// ReasonInvalid is a claim whose destinations could not be set up from its spec
+	ReasonInvalid = "invalid"
+	// ReasonSource is a property that could not be generated or read from the store
+	ReasonSource = "source"
+	// ReasonDestination is a destination the values could not be written to
+	ReasonDestination = "destination"
+	// ReasonPrune is a removed destination that could not be cleaned up
+	ReasonPrune = "prune"
+	// ReasonStatus is a claim status that could not be updated
+	ReasonStatus = "status"
+)
+
+var reasons = []string{ReasonStore, ReasonInvalid, ReasonSource, ReasonDestination, ReasonPrune, ReasonStatus}
+
+// Kinds of certificates, the certificate label of CertificateExpiry
+const (
+	CertificateCA      = "ca"
+	CertificateServing = "serving"
+	CertificateClient  = "client"
+)
+
+var (
+	// SecretsGenerated counts generated property values by generator
+	SecretsGenerated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "secrets_generated_total",
+		Help:      "Number of property values generated, by generator.",
+	}, []string{"generator"})
+
+	// SecretsRotated counts generated values that replaced a value already written for the property
+	SecretsRotated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "secrets_rotated_total",
+		Help:      "Number of generated property values that replaced a previous value, by generator.",
+	}, []string{"generator"})
+
+	// ClaimSyncErrors counts failed claim reconciles by reason
+	ClaimSyncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "claim_sync_errors_total",
+		Help:      "Number of failed claim syncs, by claim and reason.",
+	}, []string{"kind", "namespace", "claim", "reason"})
+
+	// CertificateExpiry is the expiry of the certificates of the internal CA, by the Secret
+	// holding them
+	CertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Unix time the certificate expires at.",
+	}, []string{"certificate", "namespace", "name"})
+
+	// ProviderRequestDuration observes the calls to secret stores. The result label is
+	// success, not_found or error.
+	ProviderRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Duration of secret store calls, by store, operation and result.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"store_kind", "namespace", "store", "provider", "operation", "result"})
+
+	rotations = &rotationCollector{
+		desc: prometheus.NewDesc(namespace+"_claim_property_rotation_age_seconds",
+			"Seconds since the value of a generated claim property was last generated.",
+			[]string{"kind", "namespace", "claim", "property"}, nil),
+		rotated: map[propertyKey]time.Time{},
+	}
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		SecretsGenerated,
+		SecretsRotated,
+		ClaimSyncErrors,
+		CertificateExpiry,
+		ProviderRequestDuration,
+		rotations,
+	)
+}
+
+// RecordGenerated counts a generated value, rotated is true if it replaced a previous value
+func RecordGenerated(generator string, rotated bool) {
+	SecretsGenerated.WithLabelValues(generator).Inc()
+	if rotated {
+		SecretsRotated.WithLabelValues(generator).Inc()
+	}
+}
+
+// ClaimSyncError counts a failed sync of the claim kind namespace/name
+func ClaimSyncError(kind, namespace, name, reason string) {
+	ClaimSyncErrors.WithLabelValues(kind, namespace, name, reason).Inc()
+}
+
+// SetCertificateExpiry records the expiry of the certificate held by the Secret namespace/name
+func SetCertificateExpiry(certificate, namespace, name string, notAfter time.Time) {
+	CertificateExpiry.WithLabelValues(certificate, namespace, name).Set(float64(notAfter.Unix()))
+}
+
+// ForgetCertificate removes the expiry of a certificate that is no longer used, so it does
+// not trigger expiry alerts
+func ForgetCertificate(certificate, namespace, name string) {
+	CertificateExpiry.DeleteLabelValues(certificate, namespace, name)
+}
+
+// SetRotated records the time the value of a claim property was generated
+func SetRotated(kind, namespace, claim, property string, at time.Time) {
+	rotations.set(propertyKey{kind, namespace, claim, property}, at, true)
+}
+
+// SetRotatedIfUnknown records the time a property was generated unless it is already known,
+// e.g. for values kept from an existing Secret after the operator restarted
+func SetRotatedIfUnknown(kind, namespace, claim, property string, at time.Time) {
+	rotations.set(propertyKey{kind, namespace, claim, property}, at, false)
+}
+
+// ForgetClaim removes the series of a deleted claim
+func ForgetClaim(kind, namespace, claim string) {
+	rotations.forget(kind, namespace, claim)
+	for _, reason := range reasons {
+		ClaimSyncErrors.DeleteLabelValues(kind, namespace, claim, reason)
+	}
+}
+
+type propertyKey struct {
+	kind, namespace, claim, property string
+}
+
+// rotationCollector reports the age of generated values at scrape time, so alert rules can
+// compare it to a threshold directly
+type rotationCollector struct {
+	desc *prometheus.Desc
+
+	mu      sync.Mutex
+	rotated map[propertyKey]time.Time
+}
+
+func (c *rotationCollector) set(key propertyKey, at time.Time, overwrite bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.rotated[key]; ok && !overwrite {
+		return
+	}
+	c.rotated[key] = at
+}
+
+func (c *rotationCollector) forget(kind, namespace, claim string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.rotated {
+		if key.kind == kind && key.namespace == namespace && key.claim == claim {
+			delete(c.rotated, key)
+		}
+	}
+}
+
+func (c *rotationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *rotationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, at := range c.rotated {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(at).Seconds(),
+			key.kind, key.namespace, key.claim, key.property)
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// instrumentedClient observes the calls of a providers.Client in ProviderRequestDuration
+type instrumentedClient struct {
+	client providers.Client
+	labels prometheus.Labels
+}
+
+var _ providers.Client = &instrumentedClient{}
+
+// InstrumentClient returns a client observing the calls to the store's client
+func InstrumentClient(client providers.Client, store v1alpha1.GenericStore) providers.Client {
+	kind := v1alpha1.SecretStoreKind
+	if _, ok := store.(*v1alpha1.ClusterSecretStore); ok {
+		kind = v1alpha1.ClusterSecretStoreKind
+	}
+	provider := ""
+	if names := v1alpha1.ConfiguredProviders(&store.GetSpec().Provider); len(names) == 1 {
+		provider = names[0]
+	}
+	return &instrumentedClient{client: client, labels: prometheus.Labels{
+		"store_kind": kind,
+		"namespace":  store.GetNamespace(),
+		"store":      store.GetName(),
+		"provider":   provider,
+	}}
+}
+
+func (c *instrumentedClient) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	start := time.Now()
+	value, err := c.client.GetSecret(ctx, name, version)
+	c.observe("get", start, err)
+	return value, err
+}
+
+func (c *instrumentedClient) PutSecret(ctx context.Context, name string, value []byte) error {
+	start := time.Now()
+	err := c.client.PutSecret(ctx, name, value)
+	c.observe("put", start, err)
+	return err
+}
+
+func (c *instrumentedClient) DeleteSecret(ctx context.Context, name string) error {
+	start := time.Now()
+	err := c.client.DeleteSecret(ctx, name)
+	c.observe("delete", start, err)
+	return err
+}
+
+func (c *instrumentedClient) ListSecrets(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	names, err := c.client.ListSecrets(ctx)
+	c.observe("list", start, err)
+	return names, err
+}
+
+func (c *instrumentedClient) observe(operation string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, providers.ErrNotFound):
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+	labels := prometheus.Labels{"operation": operation, "result": result}
+	for name, value := range c.labels {
+		labels[name] = value
+	}
+	ProviderRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,55 @@
+package ownership
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Secrets written by a claim are tracked with labels rather than owner references,
+// since owner references cannot cross namespaces. The finalizer on the claim makes
+// sure the Secrets are cleaned up before the claim goes away.
+const (
+	ClaimNameLabel      = "secret-operator.io/claim-name"
+	ClaimNamespaceLabel = "secret-operator.io/claim-namespace"
+
+	Finalizer = "secret-operator.io/secret-cleanup"
+)
+
+// Labels returns the labels that mark an object as owned by the given claim.
+func Labels(claim metav1.Object) map[string]string {
+	return map[string]string{
+		ClaimNameLabel:      claim.GetName(),
+		ClaimNamespaceLabel: claim.GetNamespace(),
+	}
+}
+
+// WithLabels returns a copy of labels with the ownership labels of the claim added.
+func WithLabels(labels map[string]string, claim metav1.Object) map[string]string {
+	merged := make(map[string]string, len(labels)+2)
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range Labels(claim) {
+		merged[k] = v
+	}
+	return merged
+}
+
+// HasLabels returns true if the object carries the ownership labels of the claim.
+func HasLabels(object metav1.Object, claim metav1.Object) bool {
+	labels := object.GetLabels()
+	return labels[ClaimNameLabel] == claim.GetName() && labels[ClaimNamespaceLabel] == claim.GetNamespace()
+}
+
+// IsOwnedBy returns true if the object carries the ownership labels of the claim, or a
+// legacy owner reference to it from before ownership was tracked with labels.
+func IsOwnedBy(object metav1.Object, claim metav1.Object) bool {
+	if HasLabels(object, claim) {
+		return true
+	}
+	for _, ownerRef := range object.GetOwnerReferences() {
+		if ownerRef.UID == claim.GetUID() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package all
+
+import (
+	// Providers register themselves in init. Importing this package registers every
+	// provider built into the operator.
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/aws"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/azure"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/fake"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/gcp"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/kubernetes"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/sops"
+	_ "github.com/secrets-operator/secrets-operator/pkg/providers/vault"
+)
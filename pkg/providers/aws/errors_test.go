@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// awsResponse is the answer of the AWS JSON protocol endpoint started by startAWS
+type awsResponse struct {
+	status int
+	body   interface{}
+}
+
+// startAWS serves every request with response, for the operation named by the X-Amz-Target header
+func startAWS(t *testing.T, responses map[string]awsResponse) Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.Header.Get("X-Amz-Target")]
+		if !ok {
+			t.Errorf("unexpected operation %s", r.Header.Get("X-Amz-Target"))
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(response.status)
+		_ = json.NewEncoder(w).Encode(response.body)
+	}))
+	t.Cleanup(server.Close)
+	return Config{Region: "eu-west-1", Endpoint: server.URL, AccessKeyId: "key", SecretAccessKey: "secret"}
+}
+
+func awsError(status int, code string) awsResponse {
+	return awsResponse{status: status, body: map[string]string{"__type": code, "message": code}}
+}
+
+// errorTest is an answer of AWS to a read and how the clients report it
+type errorTest struct {
+	name          string
+	response      awsResponse
+	wantNotFound  bool
+	wantThrottled bool
+}
+
+var errorTests = []errorTest{
+	{name: "throttling code", response: awsError(http.StatusBadRequest, "ThrottlingException"), wantThrottled: true},
+	{name: "429 with an unknown code", response: awsError(http.StatusTooManyRequests, "RateExceeded"), wantThrottled: true},
+	{name: "access denied", response: awsError(http.StatusBadRequest, "AccessDeniedException")},
+	{name: "internal error", response: awsError(http.StatusInternalServerError, "InternalServiceError")},
+}
+
+func checkError(t *testing.T, err error, wantNotFound, wantThrottled bool) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	if got := errors.Is(err, providers.ErrNotFound); got != wantNotFound {
+		t.Errorf("got not found %v for %v, want %v", got, err, wantNotFound)
+	}
+	var throttled *providers.ThrottledError
+	if got := errors.As(err, &throttled); got != wantThrottled {
+		t.Errorf("got throttled %v for %v, want %v", got, err, wantThrottled)
+	}
+}
+
+func newTestSecretsManagerClient(t *testing.T, config Config) *SecretsManagerClient {
+	t.Helper()
+	c, err := NewSecretsManagerClient(config, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Throttled requests are retried by the reconcilers, not by the SDK within the test
+	c.api.Retryer = client.NoOpRetryer{}
+	return c
+}
+
+func newTestParameterStoreClient(t *testing.T, config Config) *ParameterStoreClient {
+	t.Helper()
+	c, err := NewParameterStoreClient(config, "/app", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.api.Retryer = client.NoOpRetryer{}
+	return c
+}
+
+func TestSecretsManagerClientErrors(t *testing.T) {
+	tests := append(errorTests, errorTest{
+		name: "not found", response: awsError(http.StatusBadRequest, "ResourceNotFoundException"), wantNotFound: true,
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := startAWS(t, map[string]awsResponse{"secretsmanager.GetSecretValue": tt.response})
+			_, err := newTestSecretsManagerClient(t, config).GetSecret(context.Background(), "db", "")
+			checkError(t, err, tt.wantNotFound, tt.wantThrottled)
+		})
+	}
+}
+
+func TestSecretsManagerClientGetSecret(t *testing.T) {
+	config := startAWS(t, map[string]awsResponse{"secretsmanager.GetSecretValue": {
+		status: http.StatusOK,
+		body:   map[string]string{"Name": "db", "SecretString": "s3cr3t"},
+	}})
+	got, err := newTestSecretsManagerClient(t, config).GetSecret(context.Background(), "db", "AWSPREVIOUS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "s3cr3t" {
+		t.Errorf("got %q, want %q", got, "s3cr3t")
+	}
+}
+
+func TestParameterStoreClientErrors(t *testing.T) {
+	tests := append(errorTests, errorTest{
+		name: "not found", response: awsError(http.StatusBadRequest, "ParameterNotFound"), wantNotFound: true,
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := startAWS(t, map[string]awsResponse{"AmazonSSM.GetParameter": tt.response})
+			_, err := newTestParameterStoreClient(t, config).GetSecret(context.Background(), "db", "")
+			checkError(t, err, tt.wantNotFound, tt.wantThrottled)
+		})
+	}
+}
+
+func TestParameterStoreClientGetSecret(t *testing.T) {
+	config := startAWS(t, map[string]awsResponse{"AmazonSSM.GetParameter": {
+		status: http.StatusOK,
+		body:   map[string]interface{}{"Parameter": map[string]string{"Name": "/app/db", "Value": "s3cr3t"}},
+	}})
+	got, err := newTestParameterStoreClient(t, config).GetSecret(context.Background(), "db", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "s3cr3t" {
+		t.Errorf("got %q, want %q", got, "s3cr3t")
+	}
+}
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// ParameterStoreClient is a providers.Client storing secrets as SecureString parameters
+// below a path of the SSM Parameter Store
+type ParameterStoreClient struct {
+	api      *ssm.SSM
+	path     string
+	kmsKeyId string
+}
+
+var _ providers.Client = &ParameterStoreClient{}
+
+// NewParameterStoreClient returns a client for the parameters below parameterPath. kmsKeyId
+// encrypts the parameters and may be empty.
+func NewParameterStoreClient(config Config, parameterPath, kmsKeyId string) (*ParameterStoreClient, error) {
+	sess, err := newSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &ParameterStoreClient{api: ssm.New(sess), path: path.Join("/", parameterPath), kmsKeyId: kmsKeyId}, nil
+}
+
+// GetSecret reads a parameter version or label
+func (c *ParameterStoreClient) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	selector := c.parameterName(name)
+	if version != "" {
+		selector += ":" + version
+	}
+	out, err := c.api.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(selector),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, parameterStoreError(name, err)
+	}
+	return []byte(aws.StringValue(out.Parameter.Value)), nil
+}
+
+func (c *ParameterStoreClient) PutSecret(ctx context.Context, name string, value []byte) error {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(c.parameterName(name)),
+		Value:     aws.String(string(value)),
+		Type:      aws.String(ssm.ParameterTypeSecureString),
+		Overwrite: aws.Bool(true),
+	}
+	if c.kmsKeyId != "" {
+		input.KeyId = aws.String(c.kmsKeyId)
+	}
+	_, err := c.api.PutParameterWithContext(ctx, input)
+	return parameterStoreError(name, err)
+}
+
+func (c *ParameterStoreClient) DeleteSecret(ctx context.Context, name string) error {
+	_, err := c.api.DeleteParameterWithContext(ctx, &ssm.DeleteParameterInput{Name: aws.String(c.parameterName(name))})
+	return parameterStoreError(name, err)
+}
+
+// ListSecrets returns the parameters directly below the path
+func (c *ParameterStoreClient) ListSecrets(ctx context.Context) ([]string, error) {
+	var names []string
+	input := &ssm.GetParametersByPathInput{Path: aws.String(c.path), Recursive: aws.Bool(false)}
+	err := c.api.GetParametersByPathPagesWithContext(ctx, input, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, parameter := range page.Parameters {
+			names = append(names, strings.TrimPrefix(aws.StringValue(parameter.Name), strings.TrimSuffix(c.path, "/")+"/"))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parameter store list failed: %w", err)
+	}
+	return names, nil
+}
+
+func (c *ParameterStoreClient) parameterName(name string) string {
+	return path.Join(c.path, name)
+}
+
+func parameterStoreError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && (aerr.Code() == ssm.ErrCodeParameterNotFound || aerr.Code() == ssm.ErrCodeParameterVersionNotFound) {
+		return fmt.Errorf("parameter %s: %w", name, providers.ErrNotFound)
+	}
+	if isThrottled(err) {
+		// AWS does not say how long to wait, the request is retried with backoff.
+		return &providers.ThrottledError{Err: fmt.Errorf("parameter %s: %w", name, err)}
+	}
+	return fmt.Errorf("parameter %s: %w", name, err)
+}
@@ -0,0 +1,207 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// SecretsManagerName is the spec.provider field configuring AWS Secrets Manager
+	SecretsManagerName = "awsSecretsManager"
+	// ParameterStoreName is the spec.provider field configuring the SSM Parameter Store
+	ParameterStoreName = "awsParameterStore"
+
+	// RoleArnAnnotation binds a service account to an IAM role on EKS
+	RoleArnAnnotation = "eks.amazonaws.com/role-arn"
+)
+
+func init() {
+	providers.Register(SecretsManagerName, &SecretsManagerProvider{})
+	providers.Register(ParameterStoreName, &ParameterStoreProvider{})
+}
+
+// SecretsManagerProvider is the AWS Secrets Manager providers.Provider
+type SecretsManagerProvider struct{}
+
+var _ providers.AmbientCredentialsChecker = &SecretsManagerProvider{}
+
+func (p *SecretsManagerProvider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	return ambientCredentials(spec.AwsSecretsManager.Auth, fldPath.Child("auth"))
+}
+
+func (p *SecretsManagerProvider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.AwsSecretsManager
+	allErrs := validateRegion(provider.Region, provider.Endpoint, fldPath)
+	return append(allErrs, validateAuth(&provider.Auth, fldPath.Child("auth"))...)
+}
+
+func (p *SecretsManagerProvider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	irsaPodTemplate(store.GetSpec().Provider.AwsSecretsManager.Auth, builder)
+}
+
+func (p *SecretsManagerProvider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	return irsaServiceAccount(store.GetSpec().Provider.AwsSecretsManager.Auth, namespace)
+}
+
+func (p *SecretsManagerProvider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.AwsSecretsManager
+	config, err := resolveConfig(ctx, provider.Region, provider.Endpoint, provider.Auth, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecretsManagerClient(config, provider.KmsKeyId)
+}
+
+// ParameterStoreProvider is the SSM Parameter Store providers.Provider
+type ParameterStoreProvider struct{}
+
+var _ providers.AmbientCredentialsChecker = &ParameterStoreProvider{}
+
+func (p *ParameterStoreProvider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	return ambientCredentials(spec.AwsParameterStore.Auth, fldPath.Child("auth"))
+}
+
+func (p *ParameterStoreProvider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.AwsParameterStore
+	allErrs := validateRegion(provider.Region, provider.Endpoint, fldPath)
+	if provider.Path != "" && !strings.HasPrefix(provider.Path, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), provider.Path, "must start with /"))
+	}
+	return append(allErrs, validateAuth(&provider.Auth, fldPath.Child("auth"))...)
+}
+
+func (p *ParameterStoreProvider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	irsaPodTemplate(store.GetSpec().Provider.AwsParameterStore.Auth, builder)
+}
+
+func (p *ParameterStoreProvider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	return irsaServiceAccount(store.GetSpec().Provider.AwsParameterStore.Auth, namespace)
+}
+
+func (p *ParameterStoreProvider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.AwsParameterStore
+	config, err := resolveConfig(ctx, provider.Region, provider.Endpoint, provider.Auth, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return NewParameterStoreClient(config, provider.Path, provider.KmsKeyId)
+}
+
+func validateRegion(region, endpoint string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if region == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("region"), "region must be set"))
+	}
+	if endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("endpoint"), endpoint, "must be an absolute URL"))
+		}
+	}
+	return allErrs
+}
+
+func validateAuth(auth *v1alpha1.AwsAuth, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	staticKeys := auth.AccessKeyId != nil || auth.SecretAccessKey != nil
+	if staticKeys {
+		if auth.AccessKeyId == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("accessKeyId"), "must be set with secretAccessKey"))
+		} else {
+			allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.AccessKeyId, fldPath.Child("accessKeyId"))...)
+		}
+		if auth.SecretAccessKey == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("secretAccessKey"), "must be set with accessKeyId"))
+		} else {
+			allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.SecretAccessKey, fldPath.Child("secretAccessKey"))...)
+		}
+	}
+	if auth.Irsa != nil {
+		irsaPath := fldPath.Child("irsa")
+		if staticKeys {
+			allErrs = append(allErrs, field.Forbidden(irsaPath, "may not be combined with static keys"))
+		}
+		if auth.Irsa.ServiceAccount == "" {
+			allErrs = append(allErrs, field.Required(irsaPath.Child("serviceAccount"), "kubernetes service account must be set"))
+		}
+		allErrs = append(allErrs, validateRoleArn(auth.Irsa.RoleArn, irsaPath.Child("roleArn"))...)
+	}
+	if auth.AssumeRole != nil {
+		allErrs = append(allErrs, validateRoleArn(auth.AssumeRole.RoleArn, fldPath.Child("assumeRole", "roleArn"))...)
+	}
+	return allErrs
+}
+
+// ambientCredentials reports auth without static keys, which uses the default credential chain of the process
+func ambientCredentials(auth v1alpha1.AwsAuth, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if auth.Irsa != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("irsa"), "uses the operator's service account"))
+	}
+	if auth.AccessKeyId == nil || auth.SecretAccessKey == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("accessKeyId"), "static keys must be set, the default credential chain uses the operator's identity"))
+	}
+	return allErrs
+}
+
+func validateRoleArn(arn string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case arn == "":
+		allErrs = append(allErrs, field.Required(fldPath, "role arn must be set"))
+	case !strings.HasPrefix(arn, "arn:"):
+		allErrs = append(allErrs, field.Invalid(fldPath, arn, "must be an IAM role arn"))
+	}
+	return allErrs
+}
+
+// irsaPodTemplate runs the store agent as the IRSA service account
+func irsaPodTemplate(auth v1alpha1.AwsAuth, builder *builders.PodTemplateBuilder) {
+	if auth.Irsa != nil {
+		builder.WithServiceAccount(auth.Irsa.ServiceAccount)
+	}
+}
+
+// irsaServiceAccount returns the Kubernetes service account bound to the IAM role with IRSA
+func irsaServiceAccount(auth v1alpha1.AwsAuth, namespace string) *corev1.ServiceAccount {
+	if auth.Irsa == nil {
+		return nil
+	}
+	b := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      auth.Irsa.ServiceAccount,
+			Namespace: namespace,
+		},
+	})
+	account := b.WithAnnotations(map[string]string{RoleArnAnnotation: auth.Irsa.RoleArn}).ServiceAccount
+	return &account
+}
+
+func resolveConfig(ctx context.Context, region, endpoint string, auth v1alpha1.AwsAuth, resolve providers.ValueResolver) (Config, error) {
+	config := Config{Region: region, Endpoint: endpoint}
+	var err error
+	if auth.AccessKeyId != nil {
+		if config.AccessKeyId, err = resolve(ctx, *auth.AccessKeyId); err != nil {
+			return config, fmt.Errorf("accessKeyId: %w", err)
+		}
+	}
+	if auth.SecretAccessKey != nil {
+		if config.SecretAccessKey, err = resolve(ctx, *auth.SecretAccessKey); err != nil {
+			return config, fmt.Errorf("secretAccessKey: %w", err)
+		}
+	}
+	if auth.AssumeRole != nil {
+		config.RoleArn = auth.AssumeRole.RoleArn
+		config.ExternalId = auth.AssumeRole.ExternalId
+		config.SessionName = auth.AssumeRole.SessionName
+	}
+	return config, nil
+}
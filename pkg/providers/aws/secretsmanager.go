@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// SecretsManagerClient is a providers.Client for AWS Secrets Manager
+type SecretsManagerClient struct {
+	api      *secretsmanager.SecretsManager
+	kmsKeyId string
+}
+
+var _ providers.Client = &SecretsManagerClient{}
+
+// NewSecretsManagerClient returns a client for the region in config. kmsKeyId encrypts the
+// secrets it creates and may be empty.
+func NewSecretsManagerClient(config Config, kmsKeyId string) (*SecretsManagerClient, error) {
+	sess, err := newSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretsManagerClient{api: secretsmanager.New(sess), kmsKeyId: kmsKeyId}, nil
+}
+
+// GetSecret reads a version id, or a staging label such as AWSPREVIOUS
+func (c *SecretsManagerClient) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)}
+	switch {
+	case version == "":
+	case isStagingLabel(version):
+		input.VersionStage = aws.String(version)
+	default:
+		input.VersionId = aws.String(version)
+	}
+	out, err := c.api.GetSecretValueWithContext(ctx, input)
+	if err != nil {
+		return nil, secretsManagerError(name, err)
+	}
+	if out.SecretBinary != nil {
+		return out.SecretBinary, nil
+	}
+	return []byte(aws.StringValue(out.SecretString)), nil
+}
+
+// PutSecret stores text values as SecretString so they can be read by other tools, and
+// anything else as SecretBinary
+func (c *SecretsManagerClient) PutSecret(ctx context.Context, name string, value []byte) error {
+	put := &secretsmanager.PutSecretValueInput{SecretId: aws.String(name)}
+	if utf8.Valid(value) {
+		put.SecretString = aws.String(string(value))
+	} else {
+		put.SecretBinary = value
+	}
+	_, err := c.api.PutSecretValueWithContext(ctx, put)
+	if err = secretsManagerError(name, err); !errors.Is(err, providers.ErrNotFound) {
+		return err
+	}
+
+	create := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: put.SecretString,
+		SecretBinary: put.SecretBinary,
+	}
+	if c.kmsKeyId != "" {
+		create.KmsKeyId = aws.String(c.kmsKeyId)
+	}
+	_, err = c.api.CreateSecretWithContext(ctx, create)
+	return secretsManagerError(name, err)
+}
+
+// DeleteSecret deletes the secret without a recovery window, so that the name can be
+// claimed again right away
+func (c *SecretsManagerClient) DeleteSecret(ctx context.Context, name string) error {
+	_, err := c.api.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(name),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	return secretsManagerError(name, err)
+}
+
+func (c *SecretsManagerClient) ListSecrets(ctx context.Context) ([]string, error) {
+	var names []string
+	err := c.api.ListSecretsPagesWithContext(ctx, &secretsmanager.ListSecretsInput{},
+		func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+			for _, secret := range page.SecretList {
+				names = append(names, aws.StringValue(secret.Name))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("secrets manager list failed: %w", err)
+	}
+	return names, nil
+}
+
+func isStagingLabel(version string) bool {
+	return version == "AWSCURRENT" || version == "AWSPREVIOUS" || version == "AWSPENDING"
+}
+
+func secretsManagerError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+		return fmt.Errorf("secrets manager secret %s: %w", name, providers.ErrNotFound)
+	}
+	if isThrottled(err) {
+		return &providers.ThrottledError{Err: fmt.Errorf("secrets manager secret %s: %w", name, err)}
+	}
+	return fmt.Errorf("secrets manager secret %s: %w", name, err)
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+const defaultSessionName = "secret-operator"
+
+// Config holds the resolved AWS settings shared by the Secrets Manager and Parameter Store clients
+type Config struct {
+	Region string
+	// Endpoint overrides the endpoints of every AWS service, including STS
+	Endpoint string
+	// AccessKeyId and SecretAccessKey are static keys. When empty the default credential
+	// chain is used, which picks up IRSA web identity tokens.
+	AccessKeyId     string
+	SecretAccessKey string
+	// RoleArn is assumed with the credentials above when set
+	RoleArn     string
+	ExternalId  string
+	SessionName string
+}
+
+func newSession(config Config) (*session.Session, error) {
+	awsConfig := aws.NewConfig().WithRegion(config.Region)
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+	if config.AccessKeyId != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(config.AccessKeyId, config.SecretAccessKey, ""))
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws session: %w", err)
+	}
+	if config.RoleArn == "" {
+		return sess, nil
+	}
+
+	sessionName := config.SessionName
+	if sessionName == "" {
+		sessionName = defaultSessionName
+	}
+	assumed := stscreds.NewCredentials(sess, config.RoleArn, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		if config.ExternalId != "" {
+			p.ExternalID = aws.String(config.ExternalId)
+		}
+	})
+	return sess.Copy(&aws.Config{Credentials: assumed}), nil
+}
+
+// isThrottled reports whether AWS rejected a request because of rate limits, by the error code or
+// a 429 Too Many Requests status, which some endpoints answer with codes the SDK does not know
+func isThrottled(err error) bool {
+	if request.IsErrorThrottle(err) {
+		return true
+	}
+	var failure awserr.RequestFailure
+	return errors.As(err, &failure) && failure.StatusCode() == http.StatusTooManyRequests
+}
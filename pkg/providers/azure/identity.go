@@ -0,0 +1,99 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const imdsTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+const (
+	// FederatedTokenFileEnv and AuthorityHostEnv are injected by the workload identity webhook
+	FederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	AuthorityHostEnv      = "AZURE_AUTHORITY_HOST"
+	// DefaultFederatedTokenFile is where the workload identity webhook projects the service account token
+	DefaultFederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
+
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// managedIdentityTokenSource fetches Key Vault tokens from the instance metadata service
+type managedIdentityTokenSource struct {
+	ctx      context.Context
+	clientId string
+}
+
+func (s *managedIdentityTokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{"api-version": {"2018-02-01"}, "resource": {keyVaultResource}}
+	if s.clientId != "" {
+		query.Set("client_id", s.clientId)
+	}
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, imdsTokenURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("managed identity token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("managed identity token request returned %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	expiresIn, err := strconv.Atoi(token.ExpiresIn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expires_in in managed identity token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
+// workloadIdentityTokenSource exchanges the projected service account token for a Key Vault token.
+// The token file is read on every exchange because the kubelet rotates it.
+type workloadIdentityTokenSource struct {
+	ctx           context.Context
+	authorityHost string
+	tenantId      string
+	clientId      string
+	tokenFile     string
+}
+
+func (s *workloadIdentityTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := ioutil.ReadFile(s.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read federated token: %w", err)
+	}
+	credentials := clientcredentials.Config{
+		ClientID: s.clientId,
+		TokenURL: fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(s.authorityHost, "/"), s.tenantId),
+		Scopes:   []string{keyVaultScope},
+		EndpointParams: url.Values{
+			"client_assertion_type": {clientAssertionType},
+			"client_assertion":      {strings.TrimSpace(string(assertion))},
+		},
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+	return credentials.Token(s.ctx)
+}
@@ -0,0 +1,173 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	apiVersion           = "7.2"
+	keyVaultScope        = "https://vault.azure.net/.default"
+	keyVaultResource     = "https://vault.azure.net"
+	DefaultAuthorityHost = "https://login.microsoftonline.com"
+)
+
+// Config holds the resolved settings of an Azure Key Vault store
+type Config struct {
+	VaultName          string
+	TenantId           string
+	ClientId           string
+	ClientSecret       string
+	UseManagedIdentity bool
+	// FederatedTokenFile selects workload identity when UseManagedIdentity is set
+	FederatedTokenFile string
+	// VaultURL overrides https://<vault name>.vault.azure.net
+	VaultURL string
+	// AuthorityHost overrides DefaultAuthorityHost
+	AuthorityHost string
+}
+
+// Client is a providers.Client for the Key Vault secrets REST API
+type Client struct {
+	vaultURL string
+	http     *http.Client
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client authenticating with workload identity, managed identity or the client
+// credentials of config
+func NewClient(ctx context.Context, config Config) *Client {
+	vaultURL := config.VaultURL
+	if vaultURL == "" {
+		vaultURL = fmt.Sprintf("https://%s.vault.azure.net", config.VaultName)
+	}
+	return &Client{
+		vaultURL: strings.TrimSuffix(vaultURL, "/"),
+		http:     oauth2.NewClient(ctx, tokenSource(ctx, config)),
+	}
+}
+
+func tokenSource(ctx context.Context, config Config) oauth2.TokenSource {
+	authorityHost := config.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = DefaultAuthorityHost
+	}
+	if config.UseManagedIdentity && config.FederatedTokenFile != "" {
+		return oauth2.ReuseTokenSource(nil, &workloadIdentityTokenSource{
+			ctx:           ctx,
+			authorityHost: authorityHost,
+			tenantId:      config.TenantId,
+			clientId:      config.ClientId,
+			tokenFile:     config.FederatedTokenFile,
+		})
+	}
+	if config.UseManagedIdentity {
+		return oauth2.ReuseTokenSource(nil, &managedIdentityTokenSource{ctx: ctx, clientId: config.ClientId})
+	}
+	credentials := clientcredentials.Config{
+		ClientID:     config.ClientId,
+		ClientSecret: config.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), config.TenantId),
+		Scopes:       []string{keyVaultScope},
+	}
+	return credentials.TokenSource(ctx)
+}
+
+type secretBundle struct {
+	Value string `json:"value"`
+	Id    string `json:"id,omitempty"`
+}
+
+type secretList struct {
+	Value    []secretBundle `json:"value"`
+	NextLink string         `json:"nextLink"`
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	var secret secretBundle
+	if err := c.do(ctx, http.MethodGet, c.secretURL(name, version), nil, &secret); err != nil {
+		return nil, err
+	}
+	return []byte(secret.Value), nil
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	return c.do(ctx, http.MethodPut, c.secretURL(name, ""), secretBundle{Value: string(value)}, nil)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, c.secretURL(name, ""), nil, nil)
+}
+
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	var names []string
+	next := c.vaultURL + "/secrets?api-version=" + apiVersion
+	for next != "" {
+		var list secretList
+		if err := c.do(ctx, http.MethodGet, next, nil, &list); err != nil {
+			return nil, err
+		}
+		for _, secret := range list.Value {
+			names = append(names, path.Base(secret.Id))
+		}
+		next = list.NextLink
+	}
+	return names, nil
+}
+
+func (c *Client) secretURL(name, version string) string {
+	u := c.vaultURL + "/secrets/" + url.PathEscape(name)
+	if version != "" {
+		u += "/" + url.PathEscape(version)
+	}
+	return u + "?api-version=" + apiVersion
+}
+
+func (c *Client) do(ctx context.Context, method, u string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("key vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("key vault %s %s: %w", method, req.URL.Path, providers.ErrNotFound)
+	case resp.StatusCode >= 300:
+		var kvErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&kvErr)
+		return providers.ThrottledResponse(resp,
+			fmt.Errorf("key vault %s %s returned %d: %s %s", method, req.URL.Path, resp.StatusCode, kvErr.Error.Code, kvErr.Error.Message))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
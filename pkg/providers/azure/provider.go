@@ -0,0 +1,150 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Name is the spec.provider field configuring Azure Key Vault
+const Name = "azureKeyVault"
+
+const (
+	// UseWorkloadIdentityLabel makes the workload identity webhook inject the federated token into the pod
+	UseWorkloadIdentityLabel = "azure.workload.identity/use"
+	// ClientIdAnnotation and TenantIdAnnotation bind a service account to an Azure AD application
+	ClientIdAnnotation = "azure.workload.identity/client-id"
+	TenantIdAnnotation = "azure.workload.identity/tenant-id"
+)
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the Azure Key Vault providers.Provider
+type Provider struct{}
+
+var _ providers.AmbientCredentialsChecker = &Provider{}
+
+// AmbientCredentials reports managed identities, which are the identity of the process
+func (p *Provider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	if !spec.AzureKeyVault.Auth.UseManagedIdentity {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(fldPath.Child("auth", "useManagedIdentity"), "uses the operator's identity")}
+}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.AzureKeyVault
+	var allErrs field.ErrorList
+	if provider.VaultName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("vaultName"), "vault name must be set"))
+	}
+
+	authPath := fldPath.Child("auth")
+	allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(&provider.Auth.SubscriptionId, authPath.Child("subscriptionId"))...)
+	allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(&provider.Auth.TenantId, authPath.Child("tenantId"))...)
+	if !provider.Auth.UseManagedIdentity {
+		if provider.Auth.ClientId == nil {
+			allErrs = append(allErrs, field.Required(authPath.Child("clientId"), "must be set when useManagedIdentity is false"))
+		}
+		if provider.Auth.ClientSecret == nil {
+			allErrs = append(allErrs, field.Required(authPath.Child("clientSecret"), "must be set when useManagedIdentity is false"))
+		}
+	}
+	if provider.Auth.ClientId != nil {
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(provider.Auth.ClientId, authPath.Child("clientId"))...)
+	}
+	if provider.Auth.ClientSecret != nil {
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(provider.Auth.ClientSecret, authPath.Child("clientSecret"))...)
+	}
+
+	if wi := provider.Auth.WorkloadIdentity; wi != nil {
+		wiPath := authPath.Child("workloadIdentity")
+		if !provider.Auth.UseManagedIdentity {
+			allErrs = append(allErrs, field.Forbidden(wiPath, "requires useManagedIdentity"))
+		}
+		if wi.ServiceAccount == "" {
+			allErrs = append(allErrs, field.Required(wiPath.Child("serviceAccount"), "kubernetes service account must be set"))
+		}
+		// The ids are written to the service account annotations, so they cannot come from a secret.
+		if provider.Auth.ClientId == nil {
+			allErrs = append(allErrs, field.Required(authPath.Child("clientId"), "must be set with workloadIdentity"))
+		} else if provider.Auth.ClientId.Value == nil {
+			allErrs = append(allErrs, field.Invalid(authPath.Child("clientId"), "secretRef", "must be a value with workloadIdentity"))
+		}
+		if provider.Auth.TenantId.Value == nil {
+			allErrs = append(allErrs, field.Invalid(authPath.Child("tenantId"), "secretRef", "must be a value with workloadIdentity"))
+		}
+	}
+	return allErrs
+}
+
+// PodTemplate runs the store agent as the workload identity service account
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	if wi := store.GetSpec().Provider.AzureKeyVault.Auth.WorkloadIdentity; wi != nil {
+		builder.WithServiceAccount(wi.ServiceAccount).
+			WithLabels(map[string]string{UseWorkloadIdentityLabel: "true"})
+	}
+}
+
+// ServiceAccount returns the Kubernetes service account federated with the Azure AD application
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	auth := store.GetSpec().Provider.AzureKeyVault.Auth
+	if auth.WorkloadIdentity == nil {
+		return nil
+	}
+	annotations := map[string]string{}
+	if auth.ClientId != nil && auth.ClientId.Value != nil {
+		annotations[ClientIdAnnotation] = *auth.ClientId.Value
+	}
+	if auth.TenantId.Value != nil {
+		annotations[TenantIdAnnotation] = *auth.TenantId.Value
+	}
+	b := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      auth.WorkloadIdentity.ServiceAccount,
+			Namespace: namespace,
+		},
+	})
+	account := b.WithAnnotations(annotations).ServiceAccount
+	return &account
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.AzureKeyVault
+	config := Config{VaultName: provider.VaultName, UseManagedIdentity: provider.Auth.UseManagedIdentity}
+	var err error
+	if config.TenantId, err = resolve(ctx, provider.Auth.TenantId); err != nil {
+		return nil, fmt.Errorf("tenantId: %w", err)
+	}
+	if provider.Auth.ClientId != nil {
+		if config.ClientId, err = resolve(ctx, *provider.Auth.ClientId); err != nil {
+			return nil, fmt.Errorf("clientId: %w", err)
+		}
+	}
+	if provider.Auth.ClientSecret != nil && !provider.Auth.UseManagedIdentity {
+		if config.ClientSecret, err = resolve(ctx, *provider.Auth.ClientSecret); err != nil {
+			return nil, fmt.Errorf("clientSecret: %w", err)
+		}
+	}
+	if provider.Auth.UseManagedIdentity && provider.Auth.WorkloadIdentity != nil {
+		config.FederatedTokenFile = envOrDefault(FederatedTokenFileEnv, DefaultFederatedTokenFile)
+		config.AuthorityHost = os.Getenv(AuthorityHostEnv)
+	}
+	return NewClient(ctx, config), nil
+}
+
+func envOrDefault(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+)
+
+// ErrNotFound is returned by a Client when the requested secret does not exist
+var ErrNotFound = errors.New("secret not found")
+
+// ErrProvider matches, with errors.Is, errors marked by ProviderError
+var ErrProvider = errors.New("secret store error")
+
+// ProviderError marks err as returned by a secret store, so callers can tell store failures
+// from invalid claims. It returns nil for a nil err.
+func ProviderError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &providerError{err: err}
+}
+
+type providerError struct {
+	err error
+}
+
+func (e *providerError) Error() string { return e.err.Error() }
+
+func (e *providerError) Unwrap() error { return e.err }
+
+func (e *providerError) Is(target error) bool { return target == ErrProvider }
+
+// ErrMalformedCredentials matches, with errors.Is, errors marked by MalformedCredentials
+var ErrMalformedCredentials = errors.New("malformed credentials")
+
+// MalformedCredentials marks err as caused by resolved credentials that cannot be parsed, so
+// callers can tell them from credentials that could not be read. It returns nil for a nil err.
+func MalformedCredentials(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &malformedError{err: err}
+}
+
+type malformedError struct {
+	err error
+}
+
+func (e *malformedError) Error() string { return e.err.Error() }
+
+func (e *malformedError) Unwrap() error { return e.err }
+
+func (e *malformedError) Is(target error) bool { return target == ErrMalformedCredentials }
+
+// Client reads and writes secrets in a secret store
+type Client interface {
+	// GetSecret returns the value of the secret. An empty version reads the latest one.
+	GetSecret(ctx context.Context, name, version string) ([]byte, error)
+	// PutSecret creates the secret or adds a new version of it
+	PutSecret(ctx context.Context, name string, value []byte) error
+	DeleteSecret(ctx context.Context, name string) error
+	ListSecrets(ctx context.Context) ([]string, error)
+}
+
+// ValueResolver returns the value of a ValueOrSecretKey from a store spec
+type ValueResolver func(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error)
@@ -0,0 +1,111 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// Client is an in-memory providers.Client. Every PutSecret adds a version of the secret,
+// numbered from 1. It is safe for concurrent use and can stand in for a real store in tests
+// of code built on providers.Client.
+type Client struct {
+	mu      sync.Mutex
+	secrets map[string][][]byte
+	err     error
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client holding version 1 of each secret in data
+func NewClient(data map[string][]byte) *Client {
+	c := &Client{secrets: map[string][][]byte{}}
+	for name, value := range data {
+		c.secrets[name] = [][]byte{copyBytes(value)}
+	}
+	return c
+}
+
+// SetError makes every call return err, until it is reset with nil
+func (c *Client) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Secrets returns the latest value of every secret
+func (c *Client) Secrets() map[string][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	secrets := make(map[string][]byte, len(c.secrets))
+	for name, versions := range c.secrets {
+		secrets[name] = copyBytes(versions[len(versions)-1])
+	}
+	return secrets
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	versions, ok := c.secrets[name]
+	if !ok {
+		return nil, fmt.Errorf("fake secret %s: %w", name, providers.ErrNotFound)
+	}
+	if version == "" {
+		return copyBytes(versions[len(versions)-1]), nil
+	}
+	n, err := strconv.Atoi(version)
+	if err != nil || n < 1 || n > len(versions) {
+		return nil, fmt.Errorf("fake secret %s version %s: %w", name, version, providers.ErrNotFound)
+	}
+	return copyBytes(versions[n-1]), nil
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.secrets[name] = append(c.secrets[name], copyBytes(value))
+	return nil
+}
+
+// DeleteSecret removes the secret with all its versions
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	if _, ok := c.secrets[name]; !ok {
+		return fmt.Errorf("fake secret %s: %w", name, providers.ErrNotFound)
+	}
+	delete(c.secrets, name)
+	return nil
+}
+
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	names := make([]string, 0, len(c.secrets))
+	for name := range c.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,123 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapKey is the data key holding the versions of every secret as JSON
+const ConfigMapKey = "secrets.json"
+
+// ConfigMapClient is a providers.Client persisting its secrets in a ConfigMap. It behaves
+// like Client, the ConfigMap is created with the seeded data on first use.
+type ConfigMapClient struct {
+	client    clientset.Interface
+	namespace string
+	name      string
+	seed      map[string][]byte
+}
+
+var _ providers.Client = &ConfigMapClient{}
+
+// NewConfigMapClient returns a client for the ConfigMap namespace/name
+func NewConfigMapClient(client clientset.Interface, namespace, name string, seed map[string][]byte) *ConfigMapClient {
+	return &ConfigMapClient{client: client, namespace: namespace, name: name, seed: seed}
+}
+
+func (c *ConfigMapClient) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	secrets, _, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.GetSecret(ctx, name, version)
+}
+
+func (c *ConfigMapClient) PutSecret(ctx context.Context, name string, value []byte) error {
+	return c.update(ctx, func(secrets *Client) error {
+		return secrets.PutSecret(ctx, name, value)
+	})
+}
+
+func (c *ConfigMapClient) DeleteSecret(ctx context.Context, name string) error {
+	return c.update(ctx, func(secrets *Client) error {
+		return secrets.DeleteSecret(ctx, name)
+	})
+}
+
+func (c *ConfigMapClient) ListSecrets(ctx context.Context) ([]string, error) {
+	secrets, _, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.ListSecrets(ctx)
+}
+
+// load returns the secrets of the ConfigMap, creating it if it does not exist
+func (c *ConfigMapClient) load(ctx context.Context) (*Client, *corev1.ConfigMap, error) {
+	configMaps := c.client.CoreV1().ConfigMaps(c.namespace)
+	configMap, err := configMaps.Get(ctx, c.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secrets := NewClient(c.seed)
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: c.name, Namespace: c.namespace}}
+		if err := encode(secrets, configMap); err != nil {
+			return nil, nil, err
+		}
+		configMap, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Created concurrently, read the winner's seeded data.
+			return c.load(ctx)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create configmap %s/%s: %w", c.namespace, c.name, err)
+		}
+		return secrets, configMap, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get configmap %s/%s: %w", c.namespace, c.name, err)
+	}
+	secrets := &Client{secrets: map[string][][]byte{}}
+	if data, ok := configMap.Data[ConfigMapKey]; ok {
+		if err := json.Unmarshal([]byte(data), &secrets.secrets); err != nil {
+			return nil, nil, fmt.Errorf("configmap %s/%s: invalid %s: %w", c.namespace, c.name, ConfigMapKey, err)
+		}
+	}
+	return secrets, configMap, nil
+}
+
+// update applies change to the secrets and writes them back. A concurrent change makes the
+// update fail with a conflict instead of losing either write.
+func (c *ConfigMapClient) update(ctx context.Context, change func(*Client) error) error {
+	secrets, configMap, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	if err := change(secrets); err != nil {
+		return err
+	}
+	if err := encode(secrets, configMap); err != nil {
+		return err
+	}
+	if _, err := c.client.CoreV1().ConfigMaps(c.namespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("unable to update configmap %s/%s: %w", c.namespace, c.name, err)
+	}
+	return nil
+}
+
+func encode(secrets *Client, configMap *corev1.ConfigMap) error {
+	data, err := json.Marshal(secrets.secrets)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[ConfigMapKey] = string(data)
+	return nil
+}
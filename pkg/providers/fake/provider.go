@@ -0,0 +1,112 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Name is the spec.provider field configuring a fake store
+const Name = "fake"
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the fake providers.Provider
+type Provider struct{}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.Fake
+	var allErrs field.ErrorList
+	for name := range provider.Data {
+		if name == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("data"), name, "secret name must not be empty"))
+		}
+	}
+	if cm := provider.ConfigMap; cm != nil {
+		if cm.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("configMap", "name"), "configmap name must be set"))
+		}
+		if cm.Namespace == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("configMap", "namespace"), "configmap namespace must be set"))
+		}
+	}
+	return allErrs
+}
+
+// PodTemplate runs the store agent as the service account allowed to access the ConfigMap
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	if account := store.GetSpec().Provider.Fake.ServiceAccount; account != "" {
+		builder.WithServiceAccount(account)
+	}
+}
+
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	name := store.GetSpec().Provider.Fake.ServiceAccount
+	if name == "" {
+		return nil
+	}
+	account := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}).ServiceAccount
+	return &account
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.Fake
+	if provider.ConfigMap == nil {
+		return ClientFor(provider), nil
+	}
+	// The ConfigMap is accessed with the credentials of the process serving the store: the
+	// store agent's service account, or the operator's for stores running in process.
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load in-cluster config: %w", err)
+	}
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create kubernetes client: %w", err)
+	}
+	return NewConfigMapClient(client, provider.ConfigMap.Namespace, provider.ConfigMap.Name, seed(provider)), nil
+}
+
+var (
+	instancesMu sync.Mutex
+	instances   = map[string]*Client{}
+)
+
+// ClientFor returns the in-memory client of the process for a fake store configuration, seeded
+// on first use. Tests can use it to inspect the secrets written through a fake store.
+func ClientFor(provider *v1alpha1.FakeProvider) *Client {
+	key, _ := json.Marshal(provider)
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	client, ok := instances[string(key)]
+	if !ok {
+		client = NewClient(seed(provider))
+		instances[string(key)] = client
+	}
+	return client
+}
+
+func seed(provider *v1alpha1.FakeProvider) map[string][]byte {
+	data := make(map[string][]byte, len(provider.Data))
+	for name, value := range provider.Data {
+		data[name] = []byte(value)
+	}
+	return data
+}
@@ -0,0 +1,49 @@
+package gcp
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// CredentialsEnv points the google libraries at the mounted credentials file
+const CredentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectId    string `json:"project_id"`
+	PrivateKeyId string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ValidateServiceAccountKey checks that data is a JSON service account key. The returned
+// errors never include the key material.
+func ValidateServiceAccountKey(data []byte) error {
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return errors.New("credentials are not valid JSON")
+	}
+	if key.Type != "service_account" {
+		return fmt.Errorf("credentials have type %q, expected a service_account key", key.Type)
+	}
+	if key.ClientEmail == "" {
+		return errors.New("service account key has no client_email")
+	}
+	if key.PrivateKeyId == "" {
+		return errors.New("service account key has no private_key_id")
+	}
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return errors.New("service account key has no PEM encoded private_key")
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return errors.New("service account key has a malformed private_key")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/storeagent"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Name is the spec.provider field configuring GCP Secret Manager
+const Name = "gsm"
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the GCP Secret Manager providers.Provider
+type Provider struct{}
+
+var _ providers.CredentialsValidator = &Provider{}
+var _ providers.AmbientCredentialsChecker = &Provider{}
+
+// AmbientCredentials reports auth without a credentials file, which uses the default credentials of the process
+func (p *Provider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	if spec.GcpSecretsManager.Auth.CredentialsFile != nil {
+		return nil
+	}
+	return field.ErrorList{field.Required(fldPath.Child("auth", "credentialsFile"), "must be set, workload identity uses the operator's identity")}
+}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.GcpSecretsManager
+	var allErrs field.ErrorList
+	if provider.ProjectId == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("projectId"), "project id must be set"))
+	}
+
+	authPath := fldPath.Child("auth")
+	auth := provider.Auth
+	switch {
+	case auth.WorkloadIdentity == nil && auth.CredentialsFile == nil:
+		allErrs = append(allErrs, field.Required(authPath, "one of workloadIdentity or credentialsFile must be set"))
+	case auth.WorkloadIdentity != nil && auth.CredentialsFile != nil:
+		allErrs = append(allErrs, field.Forbidden(authPath, "only one of workloadIdentity or credentialsFile may be set"))
+	}
+	if auth.WorkloadIdentity != nil {
+		wiPath := authPath.Child("workloadIdentity")
+		if auth.WorkloadIdentity.ServiceAccount == "" {
+			allErrs = append(allErrs, field.Required(wiPath.Child("serviceAccount"), "kubernetes service account must be set"))
+		}
+		if auth.WorkloadIdentity.GcpServiceAccount == "" {
+			allErrs = append(allErrs, field.Required(wiPath.Child("gcpServiceAccount"), "gcp service account must be set"))
+		}
+	}
+	if auth.CredentialsFile != nil {
+		credentialsPath := authPath.Child("credentialsFile")
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.CredentialsFile, credentialsPath)...)
+		if value := auth.CredentialsFile.Value; value != nil {
+			if err := ValidateServiceAccountKey([]byte(*value)); err != nil {
+				allErrs = append(allErrs, field.Invalid(credentialsPath.Child("value"), "<redacted>", err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateCredentials checks that a credentials file referenced from a secret is a service account key
+func (p *Provider) ValidateCredentials(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) error {
+	_, err := credentialsJSON(ctx, spec.GcpSecretsManager.Auth, resolve)
+	return err
+}
+
+// PodTemplate runs the store agent as the Workload Identity service account, or points it
+// at the mounted credentials file
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	auth := store.GetSpec().Provider.GcpSecretsManager.Auth
+	if wi := auth.WorkloadIdentity; wi != nil {
+		builder.WithServiceAccount(wi.ServiceAccount)
+	}
+	if auth.CredentialsFile != nil && auth.CredentialsFile.SecretRef != nil {
+		builder.WithEnv(corev1.EnvVar{Name: CredentialsEnv, Value: storeagent.CredentialFile(*auth.CredentialsFile.SecretRef)})
+	}
+}
+
+// ServiceAccount returns the Kubernetes service account bound to the GCP service account with Workload Identity
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	provider := store.GetSpec().Provider.GcpSecretsManager
+	if provider.Auth.WorkloadIdentity == nil {
+		return nil
+	}
+	b := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      provider.Auth.WorkloadIdentity.ServiceAccount,
+			Namespace: namespace,
+		},
+	})
+	account := b.WithAnnotations(map[string]string{
+		"iam.gke.io/gcp-service-account": fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
+			provider.Auth.WorkloadIdentity.GcpServiceAccount,
+			provider.ProjectId),
+	}).ServiceAccount
+	return &account
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	credentials, err := credentialsJSON(ctx, spec.GcpSecretsManager.Auth, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(ctx, Config{ProjectId: spec.GcpSecretsManager.ProjectId, CredentialsJSON: credentials})
+}
+
+// credentialsJSON resolves the service account key of auth, or returns nil without a credentials file
+func credentialsJSON(ctx context.Context, auth v1alpha1.GcpSecretsManagerAuth, resolve providers.ValueResolver) ([]byte, error) {
+	if auth.CredentialsFile == nil {
+		return nil, nil
+	}
+	value, err := resolve(ctx, *auth.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("credentialsFile: %w", err)
+	}
+	if err := ValidateServiceAccountKey([]byte(value)); err != nil {
+		return nil, providers.MalformedCredentials(fmt.Errorf("credentialsFile: %w", err))
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,178 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	DefaultEndpoint = "https://secretmanager.googleapis.com/v1"
+	cloudPlatform   = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// Config holds the resolved settings of a GCP Secret Manager store
+type Config struct {
+	ProjectId string
+	// Endpoint overrides DefaultEndpoint
+	Endpoint string
+	// CredentialsJSON is a service account key. When empty the application default
+	// credentials are used.
+	CredentialsJSON []byte
+}
+
+// Client is a providers.Client for the Secret Manager REST API
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client using the service account key of config, or the application
+// default credentials, e.g. Workload Identity
+func NewClient(ctx context.Context, config Config) (*Client, error) {
+	if len(config.CredentialsJSON) > 0 {
+		credentials, err := google.CredentialsFromJSON(ctx, config.CredentialsJSON, cloudPlatform)
+		if err != nil {
+			return nil, fmt.Errorf("invalid google credentials: %w", err)
+		}
+		return NewClientWithHTTP(config, oauth2.NewClient(ctx, credentials.TokenSource)), nil
+	}
+	httpClient, err := google.DefaultClient(ctx, cloudPlatform)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find google credentials: %w", err)
+	}
+	return NewClientWithHTTP(config, httpClient), nil
+}
+
+// NewClientWithHTTP returns a client sending authenticated requests through httpClient
+func NewClientWithHTTP(config Config, httpClient *http.Client) *Client {
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultEndpoint
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &Client{config: config, http: httpClient}
+}
+
+type payload struct {
+	Data string `json:"data"`
+}
+
+type accessResponse struct {
+	Payload payload `json:"payload"`
+}
+
+type addVersionRequest struct {
+	Payload payload `json:"payload"`
+}
+
+type secretList struct {
+	Secrets []struct {
+		Name string `json:"name"`
+	} `json:"secrets"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	if version == "" {
+		version = "latest"
+	}
+	var resp accessResponse
+	u := fmt.Sprintf("%s/versions/%s:access", c.secretURL(name), url.PathEscape(version))
+	if err := c.do(ctx, http.MethodGet, u, nil, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Payload.Data)
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	add := addVersionRequest{Payload: payload{Data: base64.StdEncoding.EncodeToString(value)}}
+	err := c.do(ctx, http.MethodPost, c.secretURL(name)+":addVersion", add, nil)
+	if !errors.Is(err, providers.ErrNotFound) {
+		return err
+	}
+
+	create := map[string]interface{}{"replication": map[string]interface{}{"automatic": map[string]interface{}{}}}
+	u := fmt.Sprintf("%s/projects/%s/secrets?secretId=%s", c.config.Endpoint, url.PathEscape(c.config.ProjectId), url.QueryEscape(name))
+	if err := c.do(ctx, http.MethodPost, u, create, nil); err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, c.secretURL(name)+":addVersion", add, nil)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, c.secretURL(name), nil, nil)
+}
+
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	var names []string
+	pageToken := ""
+	for {
+		u := fmt.Sprintf("%s/projects/%s/secrets?pageToken=%s", c.config.Endpoint, url.PathEscape(c.config.ProjectId), url.QueryEscape(pageToken))
+		var list secretList
+		if err := c.do(ctx, http.MethodGet, u, nil, &list); err != nil {
+			return nil, err
+		}
+		for _, secret := range list.Secrets {
+			names = append(names, path.Base(secret.Name))
+		}
+		if list.NextPageToken == "" {
+			return names, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+func (c *Client) secretURL(name string) string {
+	return fmt.Sprintf("%s/projects/%s/secrets/%s", c.config.Endpoint, url.PathEscape(c.config.ProjectId), url.PathEscape(name))
+}
+
+func (c *Client) do(ctx context.Context, method, u string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("secret manager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("secret manager %s %s: %w", method, req.URL.Path, providers.ErrNotFound)
+	case resp.StatusCode >= 300:
+		var gcpErr struct {
+			Error struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&gcpErr)
+		return providers.ThrottledResponse(resp,
+			fmt.Errorf("secret manager %s %s returned %d: %s %s", method, req.URL.Path, resp.StatusCode, gcpErr.Error.Status, gcpErr.Error.Message))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
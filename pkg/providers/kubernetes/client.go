@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/pkg/clients/kube"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Config holds the resolved settings of a Kubernetes store
+type Config struct {
+	Namespace string
+	Key       string
+	// Kubeconfig connects with its current context. Otherwise Server, Token and CABundle are used.
+	Kubeconfig []byte
+	Server     string
+	Token      string
+	CABundle   []byte
+}
+
+// Client is a providers.Client storing each secret in the Key of a Secret of the same name
+type Client struct {
+	secrets   clientset.Interface
+	namespace string
+	key       string
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client for the cluster and namespace of config
+func NewClient(config Config) (*Client, error) {
+	restConfig, err := RESTConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create kubernetes client: %w", err)
+	}
+	return NewClientWithInterface(secrets, config.Namespace, config.Key), nil
+}
+
+// NewClientWithInterface returns a client using an existing clientset
+func NewClientWithInterface(secrets clientset.Interface, namespace, key string) *Client {
+	return &Client{secrets: secrets, namespace: namespace, key: key}
+}
+
+// RESTConfig returns the rest.Config for the kubeconfig, or the server and token of config
+func RESTConfig(config Config) (*rest.Config, error) {
+	if len(config.Kubeconfig) > 0 {
+		return kube.ConfigFromKubeconfig(config.Kubeconfig)
+	}
+	if config.Server == "" {
+		return nil, errors.New("neither kubeconfig nor server is set")
+	}
+	return kube.ConfigFromToken(config.Server, config.Token, config.CABundle)
+}
+
+// GetSecret reads the latest value, Secrets are not versioned
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	if version != "" {
+		return nil, fmt.Errorf("secret %s/%s: versions are not supported by kubernetes stores", c.namespace, name)
+	}
+	secret, err := c.secrets.CoreV1().Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, c.error(name, err)
+	}
+	value, ok := secret.Data[c.key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key %s: %w", c.namespace, name, c.key, providers.ErrNotFound)
+	}
+	return value, nil
+}
+
+// PutSecret sets the key of the Secret, keeping its other keys
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	secrets := c.secrets.CoreV1().Secrets(c.namespace)
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.namespace},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{c.key: value},
+		}, metav1.CreateOptions{})
+		return c.error(name, err)
+	} else if err != nil {
+		return c.error(name, err)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[c.key] = value
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return c.error(name, err)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	return c.error(name, c.secrets.CoreV1().Secrets(c.namespace).Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// ListSecrets returns the Secrets of the namespace that have the key
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	var names []string
+	opts := metav1.ListOptions{}
+	for {
+		list, err := c.secrets.CoreV1().Secrets(c.namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list secrets in %s: %w", c.namespace, err)
+		}
+		for _, secret := range list.Items {
+			if _, ok := secret.Data[c.key]; ok {
+				names = append(names, secret.Name)
+			}
+		}
+		if list.Continue == "" {
+			return names, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+func (c *Client) error(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("secret %s/%s: %w", c.namespace, name, providers.ErrNotFound)
+	}
+	return fmt.Errorf("secret %s/%s: %w", c.namespace, name, err)
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// startAPIServer starts an envtest API server, stopped when the test ends
+func startAPIServer(t *testing.T) *rest.Config {
+	t.Helper()
+	assets := os.Getenv("KUBEBUILDER_ASSETS")
+	if assets == "" {
+		assets = "/usr/local/kubebuilder/bin"
+	}
+	if _, err := os.Stat(filepath.Join(assets, "etcd")); err != nil {
+		t.Skipf("envtest binaries are not installed in %s", assets)
+	}
+	env := &envtest.Environment{}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("unable to start API server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("unable to stop API server: %v", err)
+		}
+	})
+	return cfg
+}
+
+// kubeconfig returns a kubeconfig whose current context connects with cfg
+func kubeconfig(t *testing.T, cfg *rest.Config) []byte {
+	t.Helper()
+	config := clientcmdapi.NewConfig()
+	config.Clusters["remote"] = &clientcmdapi.Cluster{
+		Server:                   cfg.Host,
+		CertificateAuthorityData: cfg.CAData,
+	}
+	config.AuthInfos["remote"] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: cfg.CertData,
+		ClientKeyData:         cfg.KeyData,
+		Token:                 cfg.BearerToken,
+	}
+	config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "remote"}
+	config.CurrentContext = "remote"
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestClientWritesToTheRemoteCluster(t *testing.T) {
+	local := startAPIServer(t)
+	remote := startAPIServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		secret string
+		config Config
+	}{
+		{
+			name:   "kubeconfig",
+			secret: "from-kubeconfig",
+			config: Config{Namespace: "default", Key: "value", Kubeconfig: kubeconfig(t, remote)},
+		},
+		{
+			name:   "server and token",
+			secret: "from-token",
+			config: Config{Namespace: "default", Key: "value",
+				Server: remote.Host, Token: remote.BearerToken, CABundle: remote.CAData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := tt.secret
+			if err := client.PutSecret(ctx, name, []byte("s3cr3t")); err != nil {
+				t.Fatal(err)
+			}
+			value, err := client.GetSecret(ctx, name, "")
+			if err != nil || string(value) != "s3cr3t" {
+				t.Fatalf("got %q, %v, want the written value", value, err)
+			}
+
+			remoteSecrets := clientset.NewForConfigOrDie(remote).CoreV1().Secrets("default")
+			if _, err := remoteSecrets.Get(ctx, name, metav1.GetOptions{}); err != nil {
+				t.Fatalf("secret was not written to the remote cluster: %v", err)
+			}
+			localSecrets := clientset.NewForConfigOrDie(local).CoreV1().Secrets("default")
+			if _, err := localSecrets.Get(ctx, name, metav1.GetOptions{}); err == nil {
+				t.Fatal("secret was written to the local cluster")
+			}
+
+			if err := client.DeleteSecret(ctx, name); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.GetSecret(ctx, name, ""); !errors.Is(err, providers.ErrNotFound) {
+				t.Fatalf("got %v after delete, want ErrNotFound", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Name is the spec.provider field configuring a Kubernetes store
+const Name = "kubernetes"
+
+const defaultKey = "value"
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the Kubernetes providers.Provider
+type Provider struct{}
+
+var _ providers.CredentialsValidator = &Provider{}
+var _ providers.AmbientCredentialsChecker = &Provider{}
+
+// AmbientCredentials reports auth without a kubeconfig or token, which would fall back to the
+// defaults of the process
+func (p *Provider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	auth := spec.Kubernetes.Auth
+	if auth.Kubeconfig != nil || auth.Token != nil {
+		return nil
+	}
+	return field.ErrorList{field.Required(fldPath.Child("auth"), "one of kubeconfig or token must be set")}
+}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.Kubernetes
+	var allErrs field.ErrorList
+	if provider.Namespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "namespace must be set"))
+	} else if msgs := validation.IsDNS1123Label(provider.Namespace); len(msgs) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), provider.Namespace, msgs[0]))
+	}
+	if provider.Key != "" {
+		if msgs := validation.IsConfigMapKey(provider.Key); len(msgs) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), provider.Key, msgs[0]))
+		}
+	}
+
+	authPath := fldPath.Child("auth")
+	auth := provider.Auth
+	remote := auth.Server != "" || auth.Token != nil || auth.CABundle != nil
+	switch {
+	case auth.Kubeconfig == nil && !remote:
+		allErrs = append(allErrs, field.Required(authPath, "one of kubeconfig or server must be set"))
+	case auth.Kubeconfig != nil && remote:
+		allErrs = append(allErrs, field.Forbidden(authPath, "only one of kubeconfig or server may be set"))
+	}
+	if auth.Kubeconfig != nil {
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.Kubeconfig, authPath.Child("kubeconfig"))...)
+	}
+	if remote {
+		if auth.Server == "" {
+			allErrs = append(allErrs, field.Required(authPath.Child("server"), "api server address must be set"))
+		} else if u, err := url.Parse(auth.Server); err != nil || u.Scheme != "https" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(authPath.Child("server"), auth.Server, "must be an https URL"))
+		}
+		if auth.Token == nil {
+			allErrs = append(allErrs, field.Required(authPath.Child("token"), "must be set with server"))
+		} else {
+			allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.Token, authPath.Child("token"))...)
+		}
+		if auth.CABundle == nil {
+			allErrs = append(allErrs, field.Required(authPath.Child("caBundle"), "must be set with server"))
+		} else {
+			allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(auth.CABundle, authPath.Child("caBundle"))...)
+		}
+	}
+	return allErrs
+}
+
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {}
+
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	return nil
+}
+
+// ValidateCredentials checks that a kubeconfig referenced from a secret can be loaded
+func (p *Provider) ValidateCredentials(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) error {
+	config, err := resolveConfig(ctx, spec.Kubernetes, resolve)
+	if err != nil {
+		return err
+	}
+	_, err = RESTConfig(config)
+	return providers.MalformedCredentials(err)
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	config, err := resolveConfig(ctx, spec.Kubernetes, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config)
+}
+
+func resolveConfig(ctx context.Context, provider *v1alpha1.KubernetesProvider, resolve providers.ValueResolver) (Config, error) {
+	config := Config{
+		Namespace: provider.Namespace,
+		Key:       provider.Key,
+		Server:    provider.Auth.Server,
+	}
+	if config.Key == "" {
+		config.Key = defaultKey
+	}
+	auth := provider.Auth
+	if auth.Kubeconfig != nil {
+		kubeconfig, err := resolve(ctx, *auth.Kubeconfig)
+		if err != nil {
+			return config, fmt.Errorf("kubeconfig: %w", err)
+		}
+		config.Kubeconfig = []byte(kubeconfig)
+	}
+	if auth.Token != nil {
+		token, err := resolve(ctx, *auth.Token)
+		if err != nil {
+			return config, fmt.Errorf("token: %w", err)
+		}
+		config.Token = token
+	}
+	if auth.CABundle != nil {
+		caBundle, err := resolve(ctx, *auth.CABundle)
+		if err != nil {
+			return config, fmt.Errorf("caBundle: %w", err)
+		}
+		config.CABundle = []byte(caBundle)
+	}
+	return config, nil
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Provider implements a secret backend configured by one field of the store's spec.provider
+type Provider interface {
+	// Validate checks the provider's configuration in spec, rooted at fldPath
+	Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList
+	// PodTemplate adds what the store agent needs to reach the backend, e.g. a service account
+	PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder)
+	// ServiceAccount returns the service account the store agent runs as in namespace,
+	// or nil if the agent does not need a dedicated one
+	ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount
+	// NewClient returns a client for the backend configured in spec
+	NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve ValueResolver) (Client, error)
+}
+
+// CredentialsValidator is implemented by providers that can check their resolved credentials
+// before the store is deployed, so malformed credentials are reported on the store
+type CredentialsValidator interface {
+	ValidateCredentials(ctx context.Context, spec *v1alpha1.Provider, resolve ValueResolver) error
+}
+
+// AmbientCredentialsChecker is implemented by providers that can authenticate with the identity
+// of the process calling them, e.g. its service account token or cloud workload identity.
+// AmbientCredentials reports the fields of spec doing so, namespaced stores may not use them when
+// the provider is called from the operator.
+type AmbientCredentialsChecker interface {
+	AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList
+}
+
+var registry = map[string]Provider{}
+
+// Register makes a provider available for the spec.provider field name. It is called from
+// the init function of the provider's package.
+func Register(name string, provider Provider) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("provider %s is already registered", name))
+	}
+	registry[name] = provider
+	v1alpha1.RegisterProviderValidator(name, provider.Validate)
+	if checker, ok := provider.(AmbientCredentialsChecker); ok {
+		v1alpha1.RegisterAmbientCredentialsCheck(name, checker.AmbientCredentials)
+	}
+}
+
+// ForSpec returns the registered provider configured in spec
+func ForSpec(spec *v1alpha1.Provider) (Provider, error) {
+	names := v1alpha1.ConfiguredProviders(spec)
+	if len(names) != 1 {
+		return nil, fmt.Errorf("exactly one provider must be configured, found %d", len(names))
+	}
+	provider, ok := registry[names[0]]
+	if !ok {
+		return nil, fmt.Errorf("provider %s is not registered", names[0])
+	}
+	return provider, nil
+}
+
+// NewClient returns a client for the provider configured in spec. It is used by the store
+// agent and by the manager for stores running in process.
+func NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve ValueResolver) (Client, error) {
+	provider, err := ForSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return provider.NewClient(ctx, spec, resolve)
+}
@@ -0,0 +1,302 @@
+package sops
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+var extensions = []string{".yaml", ".yml"}
+
+// Documents reads and writes encrypted documents by name, without extension
+type Documents interface {
+	List(ctx context.Context) ([]string, error)
+	// Read returns providers.ErrNotFound for missing documents
+	Read(ctx context.Context, name string) ([]byte, error)
+	Write(ctx context.Context, name string, data []byte) error
+}
+
+// Client is a providers.Client for the values of SOPS documents. A secret is named
+// <document>/<key>, nested keys are separated by slashes.
+type Client struct {
+	documents Documents
+	keys      *Keys
+	now       func() time.Time
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client decrypting documents with keys
+func NewClient(documents Documents, keys *Keys) *Client {
+	return &Client{documents: documents, keys: keys, now: time.Now}
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	if version != "" {
+		return nil, fmt.Errorf("sops secret %s: versions are not supported", name)
+	}
+	docName, path, err := splitName(name)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := c.load(ctx, docName)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := doc.get(path)
+	if !ok {
+		return nil, fmt.Errorf("sops secret %s: %w", name, providers.ErrNotFound)
+	}
+	s, err := toString(value)
+	if err != nil {
+		return nil, fmt.Errorf("sops secret %s: %w", name, err)
+	}
+	return []byte(s), nil
+}
+
+// PutSecret re-encrypts the document with the value set. New documents are encrypted for
+// the configured keys and recipients.
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	docName, path, err := splitName(name)
+	if err != nil {
+		return err
+	}
+	doc, err := c.load(ctx, docName)
+	if errors.Is(err, providers.ErrNotFound) {
+		doc, err = c.create()
+	}
+	if err != nil {
+		return err
+	}
+	if err := doc.set(path, string(value)); err != nil {
+		return fmt.Errorf("sops secret %s: %w", name, err)
+	}
+	return c.save(ctx, docName, doc)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	docName, path, err := splitName(name)
+	if err != nil {
+		return err
+	}
+	doc, err := c.load(ctx, docName)
+	if err != nil {
+		return err
+	}
+	if !doc.remove(path) {
+		return fmt.Errorf("sops secret %s: %w", name, providers.ErrNotFound)
+	}
+	return c.save(ctx, docName, doc)
+}
+
+// ListSecrets returns the keys of every document. It does not need to decrypt them.
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	docNames, err := c.documents.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, docName := range docNames {
+		data, err := c.documents.Read(ctx, docName)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := parseDocument(data)
+		if err != nil {
+			return nil, fmt.Errorf("sops document %s: %w", docName, err)
+		}
+		for _, l := range doc.leaves() {
+			names = append(names, docName+"/"+strings.Join(l.path, "/"))
+		}
+	}
+	return names, nil
+}
+
+func (c *Client) load(ctx context.Context, docName string) (*document, error) {
+	data, err := c.documents.Read(ctx, docName)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("sops document %s: %w", docName, err)
+	}
+	dataKey, err := c.keys.decryptDataKey(doc.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("sops document %s: %w", docName, err)
+	}
+	if err := doc.decrypt(dataKey); err != nil {
+		return nil, fmt.Errorf("sops document %s: %w", docName, err)
+	}
+	return doc, nil
+}
+
+func (c *Client) create() (*document, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	doc := newDocument(dataKey)
+	if err := c.keys.encryptDataKey(dataKey, &doc.metadata, c.now()); err != nil {
+		return nil, fmt.Errorf("unable to encrypt data key: %w", err)
+	}
+	return doc, nil
+}
+
+func (c *Client) save(ctx context.Context, docName string, doc *document) error {
+	data, err := doc.marshal(c.now())
+	if err != nil {
+		return fmt.Errorf("sops document %s: %w", docName, err)
+	}
+	return c.documents.Write(ctx, docName, data)
+}
+
+func splitName(name string) (string, []string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 {
+		return "", nil, fmt.Errorf("sops secret %s: name must be <document>/<key>", name)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return "", nil, fmt.Errorf("sops secret %s: name has an empty segment", name)
+		}
+	}
+	return parts[0], parts[1:], nil
+}
+
+// documentName returns the document name of a file or ConfigMap key, or false for other files
+func documentName(file string) (string, bool) {
+	for _, ext := range extensions {
+		if strings.HasSuffix(file, ext) {
+			return strings.TrimSuffix(file, ext), true
+		}
+	}
+	return "", false
+}
+
+// DirDocuments are the YAML files of a directory, e.g. a git checkout mounted into the store agent
+type DirDocuments string
+
+func (d DirDocuments) List(ctx context.Context) ([]string, error) {
+	entries, err := ioutil.ReadDir(string(d))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := documentName(entry.Name()); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (d DirDocuments) Read(ctx context.Context, name string) ([]byte, error) {
+	for _, ext := range extensions {
+		data, err := ioutil.ReadFile(filepath.Join(string(d), name+ext))
+		if os.IsNotExist(err) {
+			continue
+		}
+		return data, err
+	}
+	return nil, fmt.Errorf("sops document %s: %w", name, providers.ErrNotFound)
+}
+
+func (d DirDocuments) Write(ctx context.Context, name string, data []byte) error {
+	file := filepath.Join(string(d), name+extensions[0])
+	for _, ext := range extensions {
+		if _, err := os.Stat(filepath.Join(string(d), name+ext)); err == nil {
+			file = filepath.Join(string(d), name+ext)
+			break
+		}
+	}
+	// Replace the file atomically, so readers never see a partially written document.
+	tmp, err := ioutil.TempFile(string(d), "."+name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// ConfigMapDocuments are the YAML keys of a ConfigMap
+type ConfigMapDocuments struct {
+	Client    clientset.Interface
+	Namespace string
+	Name      string
+}
+
+func (d *ConfigMapDocuments) List(ctx context.Context) ([]string, error) {
+	configMap, err := d.Client.CoreV1().ConfigMaps(d.Namespace).Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get configmap %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	var names []string
+	for key := range configMap.Data {
+		if name, ok := documentName(key); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (d *ConfigMapDocuments) Read(ctx context.Context, name string) ([]byte, error) {
+	configMap, err := d.Client.CoreV1().ConfigMaps(d.Namespace).Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get configmap %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	for _, ext := range extensions {
+		if data, ok := configMap.Data[name+ext]; ok {
+			return []byte(data), nil
+		}
+	}
+	return nil, fmt.Errorf("sops document %s: %w", name, providers.ErrNotFound)
+}
+
+// Write updates the ConfigMap. A concurrent change makes the update fail with a conflict
+// instead of losing either write.
+func (d *ConfigMapDocuments) Write(ctx context.Context, name string, data []byte) error {
+	configMaps := d.Client.CoreV1().ConfigMaps(d.Namespace)
+	configMap, err := configMaps.Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get configmap %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	key := name + extensions[0]
+	for _, ext := range extensions {
+		if _, ok := configMap.Data[name+ext]; ok {
+			key = name + ext
+			break
+		}
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = string(data)
+	if _, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("configmap %s/%s changed while writing %s: %w", d.Namespace, d.Name, name, err)
+		}
+		return fmt.Errorf("unable to update configmap %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,500 @@
+package sops
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	metadataKey = "sops"
+	// Version is written to the metadata of documents created by the operator
+	Version = "3.7.1"
+
+	defaultUnencryptedSuffix = "_unencrypted"
+	nonceSize                = 32
+)
+
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// metadata is the sops key of a document
+type metadata struct {
+	Age               []ageKey      `yaml:"age"`
+	Pgp               []pgpKey      `yaml:"pgp"`
+	KeyGroups         []interface{} `yaml:"key_groups"`
+	LastModified      string        `yaml:"lastmodified"`
+	Mac               string        `yaml:"mac"`
+	UnencryptedSuffix string        `yaml:"unencrypted_suffix"`
+	EncryptedSuffix   string        `yaml:"encrypted_suffix"`
+	UnencryptedRegex  string        `yaml:"unencrypted_regex"`
+	EncryptedRegex    string        `yaml:"encrypted_regex"`
+	MacOnlyEncrypted  bool          `yaml:"mac_only_encrypted"`
+	Version           string        `yaml:"version"`
+}
+
+type ageKey struct {
+	Recipient string `yaml:"recipient"`
+	Enc       string `yaml:"enc"`
+}
+
+type pgpKey struct {
+	CreatedAt   string `yaml:"created_at"`
+	Enc         string `yaml:"enc"`
+	Fingerprint string `yaml:"fp"`
+}
+
+// document is a SOPS encrypted YAML document. Values keep their ciphertext until they are
+// changed, so rewriting a document only changes the values that were written.
+type document struct {
+	root     *yaml.Node
+	metadata metadata
+	dataKey  []byte
+	// plaintext holds the decrypted value of every leaf, for the MAC
+	plaintext map[*yaml.Node]interface{}
+}
+
+// leaf is a scalar value of the document with the mapping keys leading to it
+type leaf struct {
+	node *yaml.Node
+	path []string
+}
+
+// parseDocument reads the tree and metadata of a document without decrypting it
+func parseDocument(data []byte) (*document, error) {
+	var file yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+	if file.Kind != yaml.DocumentNode || len(file.Content) != 1 || file.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("document is not a yaml mapping")
+	}
+	doc := &document{root: file.Content[0]}
+	meta := doc.metadataNode()
+	if meta == nil {
+		return nil, errors.New("document has no sops metadata")
+	}
+	if err := meta.Decode(&doc.metadata); err != nil {
+		return nil, fmt.Errorf("invalid sops metadata: %w", err)
+	}
+	if len(doc.metadata.KeyGroups) > 0 {
+		return nil, errors.New("documents with key_groups are not supported")
+	}
+	return doc, nil
+}
+
+// newDocument returns an empty document encrypted with dataKey
+func newDocument(dataKey []byte) *document {
+	return &document{
+		root:      &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		metadata:  metadata{UnencryptedSuffix: defaultUnencryptedSuffix, Version: Version},
+		dataKey:   dataKey,
+		plaintext: map[*yaml.Node]interface{}{},
+	}
+}
+
+func (d *document) metadataNode() *yaml.Node {
+	for i := 0; i+1 < len(d.root.Content); i += 2 {
+		if d.root.Content[i].Value == metadataKey {
+			return d.root.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// leaves returns the scalar values of the document in order, excluding the metadata
+func (d *document) leaves() []leaf {
+	var leaves []leaf
+	var walk func(node *yaml.Node, path []string)
+	walk = func(node *yaml.Node, path []string) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if node == d.root && key == metadataKey {
+					continue
+				}
+				walk(node.Content[i+1], append(append([]string{}, path...), key))
+			}
+		case yaml.SequenceNode:
+			// Like sops, items of a sequence share the path of the sequence.
+			for _, item := range node.Content {
+				walk(item, path)
+			}
+		case yaml.ScalarNode:
+			leaves = append(leaves, leaf{node: node, path: path})
+		}
+	}
+	walk(d.root, nil)
+	return leaves
+}
+
+// encrypted reports whether the value at path is encrypted under the rules of the metadata
+func (d *document) encrypted(path []string) bool {
+	encrypted := true
+	if suffix := d.metadata.UnencryptedSuffix; suffix != "" {
+		for _, key := range path {
+			if strings.HasSuffix(key, suffix) {
+				encrypted = false
+				break
+			}
+		}
+	}
+	if suffix := d.metadata.EncryptedSuffix; suffix != "" {
+		encrypted = anyKey(path, func(key string) bool { return strings.HasSuffix(key, suffix) })
+	}
+	if pattern := d.metadata.EncryptedRegex; pattern != "" {
+		if re, err := regexp.Compile(pattern); err == nil {
+			encrypted = anyKey(path, re.MatchString)
+		}
+	}
+	if pattern := d.metadata.UnencryptedRegex; pattern != "" {
+		if re, err := regexp.Compile(pattern); err == nil && anyKey(path, re.MatchString) {
+			encrypted = false
+		}
+	}
+	return encrypted
+}
+
+func anyKey(path []string, match func(string) bool) bool {
+	for _, key := range path {
+		if match(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// decrypt decrypts every value with dataKey and verifies the MAC of the document
+func (d *document) decrypt(dataKey []byte) error {
+	d.dataKey = dataKey
+	d.plaintext = map[*yaml.Node]interface{}{}
+	for _, l := range d.leaves() {
+		if !d.encrypted(l.path) {
+			value, err := scalarValue(l.node)
+			if err != nil {
+				return fmt.Errorf("%s: %w", strings.Join(l.path, "."), err)
+			}
+			d.plaintext[l.node] = value
+			continue
+		}
+		value, err := decryptValue(l.node.Value, dataKey, additionalData(l.path))
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(l.path, "."), err)
+		}
+		d.plaintext[l.node] = value
+	}
+
+	mac, err := decryptValue(d.metadata.Mac, dataKey, d.metadata.LastModified)
+	if err != nil {
+		return fmt.Errorf("unable to decrypt mac: %w", err)
+	}
+	if expected, err := d.mac(); err != nil {
+		return err
+	} else if mac != expected {
+		return errors.New("mac mismatch, the document was modified without sops")
+	}
+	return nil
+}
+
+// mac is the hash of the plaintext values, as computed by sops
+func (d *document) mac() (string, error) {
+	hash := sha512.New()
+	for _, l := range d.leaves() {
+		if d.metadata.MacOnlyEncrypted && !d.encrypted(l.path) {
+			continue
+		}
+		b, err := toBytes(d.plaintext[l.node])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", strings.Join(l.path, "."), err)
+		}
+		hash.Write(b)
+	}
+	return fmt.Sprintf("%X", hash.Sum(nil)), nil
+}
+
+// get returns the plaintext of the scalar at path
+func (d *document) get(path []string) (interface{}, bool) {
+	node := d.root
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode || (i == 0 && key == metadataKey) {
+			return nil, false
+		}
+		node = mappingValue(node, key)
+		if node == nil {
+			return nil, false
+		}
+	}
+	if node.Kind != yaml.ScalarNode {
+		return nil, false
+	}
+	value, ok := d.plaintext[node]
+	return value, ok
+}
+
+// set encrypts value into the scalar at path, creating the mappings leading to it
+func (d *document) set(path []string, value string) error {
+	if path[0] == metadataKey {
+		return fmt.Errorf("%s is reserved for the sops metadata", metadataKey)
+	}
+	node := d.root
+	for _, key := range path[:len(path)-1] {
+		next := mappingValue(node, key)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+		}
+		if next.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", key)
+		}
+		node = next
+	}
+	key := path[len(path)-1]
+	scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if d.encrypted(path) {
+		encrypted, err := encryptValue(value, d.dataKey, additionalData(path))
+		if err != nil {
+			return err
+		}
+		scalar.Value = encrypted
+	}
+	if existing := mappingValue(node, key); existing != nil {
+		delete(d.plaintext, existing)
+		*existing = *scalar
+		scalar = existing
+	} else {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, scalar)
+	}
+	d.plaintext[scalar] = value
+	return nil
+}
+
+// remove deletes the value at path and reports whether it existed
+func (d *document) remove(path []string) bool {
+	node := d.root
+	for _, key := range path[:len(path)-1] {
+		if node = mappingValue(node, key); node == nil || node.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	key := path[len(path)-1]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && !(node == d.root && key == metadataKey) {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// marshal updates the MAC and returns the encrypted document
+func (d *document) marshal(now time.Time) ([]byte, error) {
+	d.metadata.LastModified = now.UTC().Format(time.RFC3339)
+	mac, err := d.mac()
+	if err != nil {
+		return nil, err
+	}
+	if d.metadata.Mac, err = encryptValue(mac, d.dataKey, d.metadata.LastModified); err != nil {
+		return nil, err
+	}
+
+	meta := d.metadataNode()
+	if meta == nil {
+		meta = &yaml.Node{}
+		if err := meta.Encode(newMetadataFields(d.metadata)); err != nil {
+			return nil, err
+		}
+		d.root.Content = append(d.root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: metadataKey}, meta)
+	} else {
+		// Keep the metadata of sops versions and key types this provider does not know.
+		setMappingValue(meta, "lastmodified", d.metadata.LastModified)
+		setMappingValue(meta, "mac", d.metadata.Mac)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(d.root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// metadataFields orders the metadata of new documents like sops does
+type metadataFields struct {
+	Kms               []interface{} `yaml:"kms"`
+	GcpKms            []interface{} `yaml:"gcp_kms"`
+	AzureKv           []interface{} `yaml:"azure_kv"`
+	HcVault           []interface{} `yaml:"hc_vault"`
+	Age               []ageKey      `yaml:"age"`
+	LastModified      string        `yaml:"lastmodified"`
+	Mac               string        `yaml:"mac"`
+	Pgp               []pgpKey      `yaml:"pgp"`
+	UnencryptedSuffix string        `yaml:"unencrypted_suffix"`
+	Version           string        `yaml:"version"`
+}
+
+func newMetadataFields(m metadata) metadataFields {
+	return metadataFields{
+		Kms:               []interface{}{},
+		GcpKms:            []interface{}{},
+		AzureKv:           []interface{}{},
+		HcVault:           []interface{}{},
+		Age:               m.Age,
+		LastModified:      m.LastModified,
+		Mac:               m.Mac,
+		Pgp:               m.Pgp,
+		UnencryptedSuffix: m.UnencryptedSuffix,
+		Version:           m.Version,
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key, value string) {
+	if existing := mappingValue(node, key); existing != nil {
+		existing.Kind, existing.Tag, existing.Value, existing.Style = yaml.ScalarNode, "!!str", value, 0
+		return
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+// additionalData authenticates a value with its path, so values cannot be moved between keys
+func additionalData(path []string) string {
+	return strings.Join(path, ":") + ":"
+}
+
+func scalarValue(node *yaml.Node) (interface{}, error) {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errors.New("null values are not supported")
+	}
+	return value, nil
+}
+
+// toBytes converts a value the way sops does before hashing it into the MAC
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case int:
+		return []byte(strconv.Itoa(v)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case bool:
+		return []byte(strings.Title(strconv.FormatBool(v))), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// toString returns the secret value of a plaintext scalar
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		b, err := toBytes(v)
+		return string(b), err
+	}
+}
+
+func encryptValue(plaintext string, key []byte, additionalData string) (string, error) {
+	// sops leaves empty strings unencrypted
+	if plaintext == "" {
+		return "", nil
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, nonceSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+	out := gcm.Seal(nil, iv, []byte(plaintext), []byte(additionalData))
+	data, tag := out[:len(out)-gcm.Overhead()], out[len(out)-gcm.Overhead():]
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]",
+		base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(tag)), nil
+}
+
+func decryptValue(ciphertext string, key []byte, additionalData string) (interface{}, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+	match := encryptedValue.FindStringSubmatch(ciphertext)
+	if match == nil {
+		return nil, errors.New("value is not encrypted by sops")
+	}
+	var parts [3][]byte
+	for i := range parts {
+		var err error
+		if parts[i], err = base64.StdEncoding.DecodeString(match[i+1]); err != nil {
+			return nil, fmt.Errorf("invalid encrypted value: %w", err)
+		}
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != nonceSize {
+		return nil, errors.New("invalid encrypted value: unexpected iv size")
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return nil, errors.New("unable to decrypt value, it was encrypted with another data key or moved")
+	}
+
+	switch valueType := match[4]; valueType {
+	case "str", "comment":
+		return string(plaintext), nil
+	case "int":
+		return strconv.Atoi(string(plaintext))
+	case "float":
+		return strconv.ParseFloat(string(plaintext), 64)
+	case "bool":
+		return strconv.ParseBool(string(plaintext))
+	case "bytes":
+		return plaintext, nil
+	default:
+		return nil, fmt.Errorf("unknown value type %s", valueType)
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, nonceSize)
+}
@@ -0,0 +1,142 @@
+package sops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	agearmor "filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgparmor "github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+const dataKeySize = 32
+
+// Keys decrypt the data keys of documents and encrypt the data keys of new documents
+type Keys struct {
+	ageIdentities []age.Identity
+	ageRecipients []age.Recipient
+	pgpKeyring    openpgp.EntityList
+}
+
+// ParseKeys parses an age identity file and an armored PGP private key, either may be empty.
+// New documents are encrypted for extraAgeRecipients and the parsed keys.
+func ParseKeys(ageIdentities, pgpPrivateKey string, extraAgeRecipients []string) (*Keys, error) {
+	keys := &Keys{}
+	if ageIdentities != "" {
+		identities, err := age.ParseIdentities(strings.NewReader(ageIdentities))
+		if err != nil {
+			return nil, fmt.Errorf("invalid age identities: %w", err)
+		}
+		keys.ageIdentities = identities
+		for _, identity := range identities {
+			if x25519, ok := identity.(*age.X25519Identity); ok {
+				keys.ageRecipients = append(keys.ageRecipients, x25519.Recipient())
+			}
+		}
+	}
+	for _, r := range extraAgeRecipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %s: %w", r, err)
+		}
+		keys.ageRecipients = append(keys.ageRecipients, recipient)
+	}
+	if pgpPrivateKey != "" {
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(pgpPrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pgp private key: %w", err)
+		}
+		keys.pgpKeyring = keyring
+	}
+	if len(keys.ageIdentities) == 0 && len(keys.pgpKeyring) == 0 {
+		return nil, errors.New("neither an age identity nor a pgp key is configured")
+	}
+	return keys, nil
+}
+
+// decryptDataKey returns the data key of a document from the first key that can decrypt it
+func (k *Keys) decryptDataKey(m metadata) ([]byte, error) {
+	var errs []string
+	if len(k.ageIdentities) > 0 {
+		for _, key := range m.Age {
+			plaintext, err := age.Decrypt(agearmor.NewReader(strings.NewReader(key.Enc)), k.ageIdentities...)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("age %s: %v", key.Recipient, err))
+				continue
+			}
+			return ioutil.ReadAll(plaintext)
+		}
+	}
+	if len(k.pgpKeyring) > 0 {
+		for _, key := range m.Pgp {
+			block, err := pgparmor.Decode(strings.NewReader(key.Enc))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("pgp %s: %v", key.Fingerprint, err))
+				continue
+			}
+			message, err := openpgp.ReadMessage(block.Body, k.pgpKeyring, nil, nil)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("pgp %s: %v", key.Fingerprint, err))
+				continue
+			}
+			return ioutil.ReadAll(message.UnverifiedBody)
+		}
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("the document is not encrypted for any configured key")
+	}
+	return nil, fmt.Errorf("unable to decrypt the data key: %s", strings.Join(errs, "; "))
+}
+
+// encryptDataKey adds the data key encrypted for every recipient to the metadata of a new document
+func (k *Keys) encryptDataKey(dataKey []byte, m *metadata, now time.Time) error {
+	for _, recipient := range k.ageRecipients {
+		var buf bytes.Buffer
+		armored := agearmor.NewWriter(&buf)
+		w, err := age.Encrypt(armored, recipient)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(dataKey); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if err := armored.Close(); err != nil {
+			return err
+		}
+		m.Age = append(m.Age, ageKey{Recipient: fmt.Sprint(recipient), Enc: buf.String() + "\n"})
+	}
+	for _, entity := range k.pgpKeyring {
+		var buf bytes.Buffer
+		armored, err := pgparmor.Encode(&buf, "PGP MESSAGE", nil)
+		if err != nil {
+			return err
+		}
+		w, err := openpgp.Encrypt(armored, openpgp.EntityList{entity}, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(dataKey); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if err := armored.Close(); err != nil {
+			return err
+		}
+		m.Pgp = append(m.Pgp, pgpKey{
+			CreatedAt:   now.UTC().Format(time.RFC3339),
+			Enc:         buf.String() + "\n",
+			Fingerprint: fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint),
+		})
+	}
+	return nil
+}
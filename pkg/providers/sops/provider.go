@@ -0,0 +1,148 @@
+package sops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"filippo.io/age"
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Name is the spec.provider field configuring SOPS documents
+const Name = "sops"
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the SOPS providers.Provider
+type Provider struct{}
+
+var _ providers.CredentialsValidator = &Provider{}
+var _ providers.AmbientCredentialsChecker = &Provider{}
+
+// AmbientCredentials reports a path, which is read and written on the file system of the process
+func (p *Provider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	if spec.Sops.Path == "" {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(fldPath.Child("path"), "is on the operator's file system")}
+}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.Sops
+	var allErrs field.ErrorList
+	switch {
+	case provider.ConfigMap == nil && provider.Path == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of configMap or path must be set"))
+	case provider.ConfigMap != nil && provider.Path != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of configMap or path may be set"))
+	}
+	if cm := provider.ConfigMap; cm != nil {
+		if cm.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("configMap", "name"), "configmap name must be set"))
+		}
+		if cm.Namespace == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("configMap", "namespace"), "configmap namespace must be set"))
+		}
+	}
+	if provider.Path != "" && !filepath.IsAbs(provider.Path) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), provider.Path, "must be an absolute path"))
+	}
+
+	keysPath := fldPath.Child("keys")
+	if provider.Keys.Age == nil && provider.Keys.Pgp == nil {
+		allErrs = append(allErrs, field.Required(keysPath, "one of age or pgp must be set"))
+	}
+	if provider.Keys.Age != nil {
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(provider.Keys.Age, keysPath.Child("age"))...)
+	}
+	if provider.Keys.Pgp != nil {
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(provider.Keys.Pgp, keysPath.Child("pgp"))...)
+	}
+	for i, recipient := range provider.AgeRecipients {
+		if _, err := age.ParseX25519Recipient(recipient); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ageRecipients").Index(i), recipient, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// PodTemplate runs the store agent as the service account allowed to access the ConfigMap
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	if account := store.GetSpec().Provider.Sops.ServiceAccount; account != "" {
+		builder.WithServiceAccount(account)
+	}
+}
+
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	name := store.GetSpec().Provider.Sops.ServiceAccount
+	if name == "" {
+		return nil
+	}
+	account := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}).ServiceAccount
+	return &account
+}
+
+// ValidateCredentials checks that the keys referenced from secrets can be parsed
+func (p *Provider) ValidateCredentials(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) error {
+	_, err := resolveKeys(ctx, spec.Sops, resolve)
+	return err
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.Sops
+	keys, err := resolveKeys(ctx, provider, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if provider.Path != "" {
+		return NewClient(DirDocuments(provider.Path), keys), nil
+	}
+
+	// The ConfigMap is read with the credentials of the process serving the store: the store
+	// agent's service account, or the operator's for stores running in process.
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load in-cluster config: %w", err)
+	}
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create kubernetes client: %w", err)
+	}
+	return NewClient(&ConfigMapDocuments{
+		Client:    client,
+		Namespace: provider.ConfigMap.Namespace,
+		Name:      provider.ConfigMap.Name,
+	}, keys), nil
+}
+
+func resolveKeys(ctx context.Context, provider *v1alpha1.SopsProvider, resolve providers.ValueResolver) (*Keys, error) {
+	var ageIdentities, pgpKey string
+	var err error
+	if provider.Keys.Age != nil {
+		if ageIdentities, err = resolve(ctx, *provider.Keys.Age); err != nil {
+			return nil, fmt.Errorf("age: %w", err)
+		}
+	}
+	if provider.Keys.Pgp != nil {
+		if pgpKey, err = resolve(ctx, *provider.Keys.Pgp); err != nil {
+			return nil, fmt.Errorf("pgp: %w", err)
+		}
+	}
+	keys, err := ParseKeys(ageIdentities, pgpKey, provider.AgeRecipients)
+	return keys, providers.MalformedCredentials(err)
+}
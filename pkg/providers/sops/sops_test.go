@@ -0,0 +1,247 @@
+package sops
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"gopkg.in/yaml.v3"
+)
+
+// The documents in testdata were encrypted with sops 3.7.3 from plain.yaml:
+//
+//	sops --encrypt --age <recipient of age.key> plain.yaml > age.sops.yaml
+//	sops --encrypt --pgp <fingerprint of pgp.asc> plain.yaml > pgp.sops.yaml
+//
+// operator-*.sops.yaml were written by the Client with -update, and sops decrypted them to the
+// values of plain.yaml as strings.
+var update = flag.Bool("update", false, "rewrite the documents written by the operator in testdata")
+
+// fixedNow is the modification time of the documents written by the operator
+var fixedNow = time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func testKeys(t *testing.T, keyType string) *Keys {
+	t.Helper()
+	var keys *Keys
+	var err error
+	switch keyType {
+	case "age":
+		keys, err = ParseKeys(readTestdata(t, "age.key"), "", nil)
+	case "pgp":
+		keys, err = ParseKeys("", readTestdata(t, "pgp.asc"), nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// plainValues are the secrets of plain.yaml by name
+var plainValues = map[string]string{
+	"database/password":    "s3cr3t",
+	"database/port":        "5432",
+	"database/tls":         "true",
+	"api_token":            "abc123",
+	"replicas_unencrypted": "3",
+}
+
+func TestReadDocumentsEncryptedBySops(t *testing.T) {
+	tests := []struct {
+		file    string
+		keyType string
+	}{
+		{file: "age.sops.yaml", keyType: "age"},
+		{file: "pgp.sops.yaml", keyType: "pgp"},
+		{file: "operator-age.sops.yaml", keyType: "age"},
+		{file: "operator-pgp.sops.yaml", keyType: "pgp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			docs := DirDocuments(t.TempDir())
+			if err := docs.Write(context.Background(), "doc", []byte(readTestdata(t, tt.file))); err != nil {
+				t.Fatal(err)
+			}
+			client := NewClient(docs, testKeys(t, tt.keyType))
+			for name, want := range plainValues {
+				value, err := client.GetSecret(context.Background(), "doc/"+name, "")
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if string(value) != want {
+					t.Errorf("%s: got %q, want %q", name, value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestModifiedDocumentsFailTheMac(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(readTestdata(t, "age.sops.yaml")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	// Unencrypted values are covered by the MAC too
+	mappingValue(doc.Content[0], "replicas_unencrypted").Value = "4"
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := DirDocuments(t.TempDir())
+	if err := docs.Write(context.Background(), "doc", data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(docs, testKeys(t, "age")).GetSecret(context.Background(), "doc/api_token", ""); err == nil {
+		t.Fatal("modified document was decrypted")
+	}
+}
+
+// writeDocument writes the values of plain.yaml to a new document with the Client
+func writeDocument(t *testing.T, keyType string) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	client := NewClient(DirDocuments(dir), testKeys(t, keyType))
+	client.now = func() time.Time { return fixedNow }
+	// In the order of plain.yaml, which orders the MAC
+	for _, name := range []string{"database/password", "database/port", "database/tls", "api_token", "replicas_unencrypted"} {
+		if err := client.PutSecret(context.Background(), "doc/"+name, []byte(plainValues[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "doc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestUpdateOperatorDocuments(t *testing.T) {
+	if !*update {
+		t.Skip("run with -update to rewrite the documents written by the operator")
+	}
+	for _, keyType := range []string{"age", "pgp"} {
+		file := filepath.Join("testdata", "operator-"+keyType+".sops.yaml")
+		if err := ioutil.WriteFile(file, writeDocument(t, keyType), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestSopsDecryptsWrittenDocuments decrypts documents written by the Client with the sops
+// binary, if it is installed
+func TestSopsDecryptsWrittenDocuments(t *testing.T) {
+	sops, err := exec.LookPath("sops")
+	if err != nil {
+		t.Skip("sops is not installed")
+	}
+	gpg, err := exec.LookPath("gpg")
+	if err != nil {
+		t.Skip("gpg is not installed")
+	}
+	gnupgHome := t.TempDir()
+	importKey := exec.Command(gpg, "--batch", "--import", filepath.Join("testdata", "pgp.asc"))
+	importKey.Env = append(os.Environ(), "GNUPGHOME="+gnupgHome)
+	if out, err := importKey.CombinedOutput(); err != nil {
+		t.Fatalf("unable to import pgp key: %v: %s", err, out)
+	}
+	ageKey, err := filepath.Abs(filepath.Join("testdata", "age.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keyType := range []string{"age", "pgp"} {
+		t.Run(keyType, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "doc.yaml")
+			if err := ioutil.WriteFile(file, writeDocument(t, keyType), 0600); err != nil {
+				t.Fatal(err)
+			}
+			decrypt := exec.Command(sops, "--decrypt", file)
+			decrypt.Env = append(os.Environ(), "GNUPGHOME="+gnupgHome, "SOPS_AGE_KEY_FILE="+ageKey)
+			out, err := decrypt.Output()
+			if err != nil {
+				t.Fatalf("sops failed to decrypt the document: %v", err)
+			}
+			var got map[string]interface{}
+			if err := yaml.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			// The Client writes every value as a string
+			want := map[string]interface{}{
+				"database":             map[string]interface{}{"password": "s3cr3t", "port": "5432", "tls": "true"},
+				"api_token":            "abc123",
+				"replicas_unencrypted": "3",
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("sops decrypted\n%s", out)
+			}
+		})
+	}
+}
+
+func TestValidateCredentialsReportsMalformedKeys(t *testing.T) {
+	ageKey := v1alpha1.ValueOrSecretKey{SecretRef: &v1alpha1.SecretRef{Namespace: "default", Name: "sops", Key: "age"}}
+	spec := &v1alpha1.Provider{Sops: &v1alpha1.SopsProvider{
+		Path: "/etc/secrets",
+		Keys: v1alpha1.SopsKeys{Age: &ageKey},
+	}}
+	unavailable := errors.New("secret default/sops is not available")
+	tests := []struct {
+		name          string
+		resolve       providers.ValueResolver
+		wantMalformed bool
+		wantErr       error
+	}{
+		{
+			name: "valid key",
+			resolve: func(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error) {
+				return readTestdata(t, "age.key"), nil
+			},
+		},
+		{
+			name: "malformed key",
+			resolve: func(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error) {
+				return "not an age identity", nil
+			},
+			wantMalformed: true,
+		},
+		{
+			name: "unreadable key",
+			resolve: func(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error) {
+				return "", unavailable
+			},
+			wantErr: unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Provider{}).ValidateCredentials(context.Background(), spec, tt.resolve)
+			if got := errors.Is(err, providers.ErrMalformedCredentials); got != tt.wantMalformed {
+				t.Errorf("got malformed %v for %v, want %v", got, err, tt.wantMalformed)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !tt.wantMalformed && tt.wantErr == nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ThrottledError is returned by a Client when the secret store rejected a request because of rate limits
+type ThrottledError struct {
+	// RetryAfter is the delay the store asked for before the next request, zero if it did not say
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *ThrottledError) Error() string { return e.Err.Error() }
+
+func (e *ThrottledError) Unwrap() error { return e.Err }
+
+// RetryAfter returns the delay a secret store asked for before retrying the request that failed with err
+func RetryAfter(err error) (time.Duration, bool) {
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) || throttled.RetryAfter <= 0 {
+		return 0, false
+	}
+	return throttled.RetryAfter, true
+}
+
+// ThrottledResponse returns err as a ThrottledError if resp is a 429 Too Many Requests, or a
+// 503 Service Unavailable with a Retry-After header. Other errors are returned unchanged.
+func ThrottledResponse(resp *http.Response, err error) error {
+	retryAfter := resp.Header.Get("Retry-After")
+	if resp.StatusCode != http.StatusTooManyRequests && (resp.StatusCode != http.StatusServiceUnavailable || retryAfter == "") {
+		return err
+	}
+	return &ThrottledError{RetryAfter: ParseRetryAfter(retryAfter, time.Now()), Err: err}
+}
+
+// ParseRetryAfter returns the delay of a Retry-After header, given in seconds or as an HTTP date.
+// It returns zero for a missing or malformed value.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
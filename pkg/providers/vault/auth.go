@@ -0,0 +1,58 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ServiceAccountTokenFile is the projected token used by Kubernetes auth
+const ServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+type loginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+// TokenLogin uses a static token
+func TokenLogin(token string) Login {
+	return func(ctx context.Context, c *Client) (string, time.Duration, error) {
+		return token, 0, nil
+	}
+}
+
+// AppRoleLogin logs in with the AppRole auth method mounted at mount
+func AppRoleLogin(mount, roleId, secretId string) Login {
+	return func(ctx context.Context, c *Client) (string, time.Duration, error) {
+		return c.login(ctx, mount, map[string]string{"role_id": roleId, "secret_id": secretId})
+	}
+}
+
+// KubernetesLogin logs in with the Kubernetes auth method mounted at mount, presenting the
+// service account token read from tokenFile on every login
+func KubernetesLogin(mount, role, tokenFile string) Login {
+	return func(ctx context.Context, c *Client) (string, time.Duration, error) {
+		jwt, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to read service account token: %w", err)
+		}
+		return c.login(ctx, mount, map[string]string{"role": role, "jwt": strings.TrimSpace(string(jwt))})
+	}
+}
+
+func (c *Client) login(ctx context.Context, mount string, body map[string]string) (string, time.Duration, error) {
+	var resp loginResponse
+	u := fmt.Sprintf("%s/v1/auth/%s/login", c.config.Server, strings.Trim(mount, "/"))
+	if err := c.send(ctx, http.MethodPost, u, "", body, &resp); err != nil {
+		return "", 0, err
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", 0, fmt.Errorf("auth/%s returned no token", mount)
+	}
+	return resp.Auth.ClientToken, time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
@@ -0,0 +1,228 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// ValueKey is the key of the KV entry holding a secret's value
+const ValueKey = "value"
+
+// Config holds the resolved settings of a Vault store
+type Config struct {
+	Server    string
+	Namespace string
+	Mount     string
+	Path      string
+	CABundle  []byte
+	// Login returns a Vault token and how long it is valid for
+	Login Login
+}
+
+// Login authenticates against Vault using client and returns a token and its lease duration
+type Login func(ctx context.Context, c *Client) (string, time.Duration, error)
+
+// Client is a providers.Client for a KV version 2 secrets engine
+type Client struct {
+	config Config
+	http   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client for the KV engine at config.Mount
+func NewClient(config Config) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.CABundle) {
+			return nil, errors.New("caBundle contains no PEM certificates")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	config.Server = strings.TrimSuffix(config.Server, "/")
+	config.Mount = strings.Trim(config.Mount, "/")
+	config.Path = strings.Trim(config.Path, "/")
+	return &Client{config: config, http: &http.Client{Transport: transport, Timeout: 30 * time.Second}}, nil
+}
+
+type kvData struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+type kvList struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	u := c.url("data", name)
+	if version != "" {
+		if _, err := strconv.Atoi(version); err != nil {
+			return nil, fmt.Errorf("vault versions are integers, got %q", version)
+		}
+		u += "?version=" + url.QueryEscape(version)
+	}
+	var resp kvData
+	if err := c.do(ctx, http.MethodGet, u, nil, &resp); err != nil {
+		return nil, err
+	}
+	// Reading a deleted version returns metadata without data.
+	value, ok := resp.Data.Data[ValueKey]
+	if !ok {
+		return nil, fmt.Errorf("vault secret %s has no %s key: %w", name, ValueKey, providers.ErrNotFound)
+	}
+	return []byte(value), nil
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	body := map[string]interface{}{"data": map[string]string{ValueKey: string(value)}}
+	return c.do(ctx, http.MethodPost, c.url("data", name), body, nil)
+}
+
+// DeleteSecret removes every version and the metadata of the secret
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, c.url("metadata", name), nil, nil)
+}
+
+// ListSecrets returns the secrets directly below the configured path. Sub-paths are skipped.
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	var resp kvList
+	err := c.do(ctx, "LIST", c.url("metadata", ""), nil, &resp)
+	if errors.Is(err, providers.ErrNotFound) {
+		// Vault answers 404 for a path without secrets.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, key := range resp.Data.Keys {
+		if !strings.HasSuffix(key, "/") {
+			names = append(names, key)
+		}
+	}
+	return names, nil
+}
+
+func (c *Client) url(kind, name string) string {
+	segments := []string{c.config.Mount, kind}
+	if c.config.Path != "" {
+		segments = append(segments, c.config.Path)
+	}
+	if name != "" {
+		segments = append(segments, url.PathEscape(name))
+	}
+	return c.config.Server + "/v1/" + path.Join(segments...)
+}
+
+// Token returns a cached token, logging in again once most of its lease has passed
+func (c *Client) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && (c.renewAt.IsZero() || time.Now().Before(c.renewAt)) {
+		return c.token, nil
+	}
+	token, lease, err := c.config.Login(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("vault login failed: %w", err)
+	}
+	c.token = token
+	c.renewAt = time.Time{}
+	if lease > 0 {
+		c.renewAt = time.Now().Add(lease * 2 / 3)
+	}
+	return token, nil
+}
+
+func (c *Client) invalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, u string, in, out interface{}) error {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.send(ctx, method, u, token, in, out)
+	if errors.Is(err, errPermissionDenied) {
+		// The token may have been revoked before its lease ended, log in once more.
+		c.invalidateToken(token)
+		if token, err = c.Token(ctx); err != nil {
+			return err
+		}
+		err = c.send(ctx, method, u, token, in, out)
+	}
+	return err
+}
+
+var errPermissionDenied = errors.New("permission denied")
+
+// send performs a request with token, which is omitted when empty, e.g. for logins
+func (c *Client) send(ctx context.Context, method, u, token string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("vault %s %s: %w", method, req.URL.Path, providers.ErrNotFound)
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("vault %s %s: %w", method, req.URL.Path, errPermissionDenied)
+	case resp.StatusCode >= 300:
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return providers.ThrottledResponse(resp,
+			fmt.Errorf("vault %s %s returned %d: %s", method, req.URL.Path, resp.StatusCode, strings.Join(vaultErr.Errors, ", ")))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
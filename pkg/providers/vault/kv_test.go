@@ -0,0 +1,252 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+)
+
+// fakeVault serves the KV v2 engine mounted at secret/ and the AppRole login of a Vault server
+type fakeVault struct {
+	mu       sync.Mutex
+	versions map[string][]string
+	tokens   map[string]bool
+	logins   int
+	// throttle answers every KV request with 429 Too Many Requests when set
+	throttle bool
+}
+
+func startVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	vault := &fakeVault{versions: map[string][]string{}, tokens: map[string]bool{}}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	return vault, server
+}
+
+func (v *fakeVault) revokeTokens() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokens = map[string]bool{}
+}
+
+func (v *fakeVault) setThrottle(throttle bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.throttle = throttle
+}
+
+func (v *fakeVault) loginCount() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.logins
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		var login map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&login); err != nil || login["role_id"] != "role" || login["secret_id"] != "secret" {
+			http.Error(w, `{"errors":["invalid role or secret id"]}`, http.StatusBadRequest)
+			return
+		}
+		v.logins++
+		token := fmt.Sprintf("token-%d", v.logins)
+		v.tokens[token] = true
+		writeJSON(w, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+	if v.throttle {
+		w.Header().Set("Retry-After", "7")
+		http.Error(w, `{"errors":["request path \"secret/\": rate limit quota exceeded"]}`, http.StatusTooManyRequests)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		name := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+		switch r.Method {
+		case http.MethodGet:
+			versions := v.versions[name]
+			version := len(versions)
+			if requested := r.URL.Query().Get("version"); requested != "" {
+				version, _ = strconv.Atoi(requested)
+			}
+			if version < 1 || version > len(versions) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+				"data": map[string]string{ValueKey: versions[version-1]},
+			}})
+		case http.MethodPost:
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, `{"errors":["invalid body"]}`, http.StatusBadRequest)
+				return
+			}
+			v.versions[name] = append(v.versions[name], body.Data[ValueKey])
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"version": len(v.versions[name])}})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case r.URL.Path == "/v1/secret/metadata" && r.Method == "LIST":
+		if len(v.versions) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var keys []string
+		for name := range v.versions {
+			if i := strings.Index(name, "/"); i >= 0 {
+				name = name[:i+1]
+			}
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	c, err := NewClient(Config{
+		Server: server.URL,
+		Mount:  defaultMount,
+		Login:  AppRoleLogin(defaultAppRoleMount, "role", "secret"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClientReadsAndWritesVersions(t *testing.T) {
+	_, server := startVault(t)
+	c := newTestClient(t, server)
+	ctx := context.Background()
+
+	if _, err := c.GetSecret(ctx, "db", ""); !errors.Is(err, providers.ErrNotFound) {
+		t.Fatalf("got %v before the first write, want %v", err, providers.ErrNotFound)
+	}
+	for _, value := range []string{"first", "second"} {
+		if err := c.PutSecret(ctx, "db", []byte(value)); err != nil {
+			t.Fatalf("put %s: %v", value, err)
+		}
+	}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr error
+	}{
+		{version: "", want: "second"},
+		{version: "1", want: "first"},
+		{version: "2", want: "second"},
+		{version: "3", wantErr: providers.ErrNotFound},
+	}
+	for _, tt := range tests {
+		got, err := c.GetSecret(ctx, "db", tt.version)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("version %q: got error %v, want %v", tt.version, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("version %q: %v", tt.version, err)
+		} else if string(got) != tt.want {
+			t.Errorf("version %q: got %q, want %q", tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := c.GetSecret(ctx, "db", "latest"); err == nil {
+		t.Error("a version that is not an integer was requested from vault")
+	}
+}
+
+func TestClientListsSecretsBelowThePath(t *testing.T) {
+	_, server := startVault(t)
+	c := newTestClient(t, server)
+	ctx := context.Background()
+
+	names, err := c.ListSecrets(ctx)
+	if err != nil || names != nil {
+		t.Fatalf("got %v, %v for an empty path, want no secrets", names, err)
+	}
+	for _, name := range []string{"db", "api", "team/db"} {
+		if err := c.PutSecret(ctx, name, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names, err = c.ListSecrets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"api", "db"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestClientLogsInAgainWhenTheTokenIsRevoked(t *testing.T) {
+	vault, server := startVault(t)
+	c := newTestClient(t, server)
+	ctx := context.Background()
+
+	if err := c.PutSecret(ctx, "db", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	// The token is revoked before its lease ends, the 403 makes the client log in once more
+	vault.revokeTokens()
+	got, err := c.GetSecret(ctx, "db", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "value" {
+		t.Errorf("got %q, want %q", got, "value")
+	}
+	if logins := vault.loginCount(); logins != 2 {
+		t.Errorf("got %d logins, want 2", logins)
+	}
+}
+
+func TestClientReportsThrottling(t *testing.T) {
+	vault, server := startVault(t)
+	c := newTestClient(t, server)
+	ctx := context.Background()
+
+	if err := c.PutSecret(ctx, "db", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	vault.setThrottle(true)
+	_, err := c.GetSecret(ctx, "db", "")
+	if delay, ok := providers.RetryAfter(err); !ok || delay != 7*time.Second {
+		t.Errorf("got retry after %v, %v for %v, want 7s", delay, ok, err)
+	}
+}
@@ -0,0 +1,142 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/builders"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Name is the spec.provider field configuring HashiCorp Vault
+const Name = "vault"
+
+const (
+	defaultMount           = "secret"
+	defaultAppRoleMount    = "approle"
+	defaultKubernetesMount = "kubernetes"
+)
+
+func init() {
+	providers.Register(Name, &Provider{})
+}
+
+// Provider is the HashiCorp Vault providers.Provider
+type Provider struct{}
+
+var _ providers.AmbientCredentialsChecker = &Provider{}
+
+// AmbientCredentials reports Kubernetes auth, which logs in with the service account token of the process
+func (p *Provider) AmbientCredentials(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	if spec.Vault.Auth.Kubernetes == nil {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(fldPath.Child("auth", "kubernetes"), "logs in with the operator's service account token")}
+}
+
+func (p *Provider) Validate(spec *v1alpha1.Provider, fldPath *field.Path) field.ErrorList {
+	provider := spec.Vault
+	var allErrs field.ErrorList
+	if provider.Server == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("server"), "vault address must be set"))
+	} else if u, err := url.Parse(provider.Server); err != nil || u.Scheme == "" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("server"), provider.Server, "must be an absolute URL"))
+	}
+
+	authPath := fldPath.Child("auth")
+	auth := provider.Auth
+	configured := 0
+	if auth.Token != nil {
+		configured++
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(&auth.Token.Token, authPath.Child("token", "token"))...)
+	}
+	if auth.AppRole != nil {
+		configured++
+		appRolePath := authPath.Child("appRole")
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(&auth.AppRole.RoleId, appRolePath.Child("roleId"))...)
+		allErrs = append(allErrs, v1alpha1.ValidateValueOrSecretKey(&auth.AppRole.SecretId, appRolePath.Child("secretId"))...)
+	}
+	if auth.Kubernetes != nil {
+		configured++
+		if auth.Kubernetes.Role == "" {
+			allErrs = append(allErrs, field.Required(authPath.Child("kubernetes", "role"), "vault role must be set"))
+		}
+	}
+	switch {
+	case configured == 0:
+		allErrs = append(allErrs, field.Required(authPath, "one of token, appRole or kubernetes must be set"))
+	case configured > 1:
+		allErrs = append(allErrs, field.Forbidden(authPath, "only one of token, appRole or kubernetes may be set"))
+	}
+	return allErrs
+}
+
+// PodTemplate runs the store agent as the service account bound to the Kubernetes auth role
+func (p *Provider) PodTemplate(store v1alpha1.GenericStore, builder *builders.PodTemplateBuilder) {
+	if k8s := store.GetSpec().Provider.Vault.Auth.Kubernetes; k8s != nil && k8s.ServiceAccount != "" {
+		builder.WithServiceAccount(k8s.ServiceAccount)
+	}
+}
+
+// ServiceAccount returns the service account presented to the Kubernetes auth method
+func (p *Provider) ServiceAccount(store v1alpha1.GenericStore, namespace string) *corev1.ServiceAccount {
+	k8s := store.GetSpec().Provider.Vault.Auth.Kubernetes
+	if k8s == nil || k8s.ServiceAccount == "" {
+		return nil
+	}
+	account := builders.NewServiceAccountBuilder(corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k8s.ServiceAccount,
+			Namespace: namespace,
+		},
+	}).ServiceAccount
+	return &account
+}
+
+func (p *Provider) NewClient(ctx context.Context, spec *v1alpha1.Provider, resolve providers.ValueResolver) (providers.Client, error) {
+	provider := spec.Vault
+	config := Config{
+		Server:    provider.Server,
+		Namespace: provider.Namespace,
+		Mount:     orDefault(provider.Mount, defaultMount),
+		Path:      provider.Path,
+		CABundle:  []byte(provider.CABundle),
+	}
+
+	auth := provider.Auth
+	switch {
+	case auth.Token != nil:
+		token, err := resolve(ctx, auth.Token.Token)
+		if err != nil {
+			return nil, fmt.Errorf("token: %w", err)
+		}
+		config.Login = TokenLogin(token)
+	case auth.AppRole != nil:
+		roleId, err := resolve(ctx, auth.AppRole.RoleId)
+		if err != nil {
+			return nil, fmt.Errorf("roleId: %w", err)
+		}
+		secretId, err := resolve(ctx, auth.AppRole.SecretId)
+		if err != nil {
+			return nil, fmt.Errorf("secretId: %w", err)
+		}
+		config.Login = AppRoleLogin(orDefault(auth.AppRole.Path, defaultAppRoleMount), roleId, secretId)
+	case auth.Kubernetes != nil:
+		config.Login = KubernetesLogin(orDefault(auth.Kubernetes.Path, defaultKubernetesMount), auth.Kubernetes.Role, ServiceAccountTokenFile)
+	default:
+		return nil, fmt.Errorf("no vault auth method configured")
+	}
+	return NewClient(config)
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
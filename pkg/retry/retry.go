@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// BaseDelay is the delay before the first retry of a failed reconcile
+	BaseDelay = 500 * time.Millisecond
+	// MaxDelay caps the exponential backoff of failed reconciles
+	MaxDelay = 5 * time.Minute
+	// JitterFactor is the largest fraction of the backoff added at random
+	JitterFactor = 0.2
+)
+
+// ErrTerminal matches, with errors.Is, errors marked by Terminal
+var ErrTerminal = errors.New("terminal error")
+
+// Terminal marks err as caused by the spec of an object, so retrying fails the same way until
+// the spec changes. It returns nil for a nil err.
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &terminalError{err: err}
+}
+
+// IsTerminal reports whether err, or an error it wraps, was marked by Terminal
+func IsTerminal(err error) bool {
+	return errors.Is(err, ErrTerminal)
+}
+
+type terminalError struct {
+	err error
+}
+
+func (e *terminalError) Error() string { return e.err.Error() }
+
+func (e *terminalError) Unwrap() error { return e.err }
+
+func (e *terminalError) Is(target error) bool { return target == ErrTerminal }
+
+// NewRateLimiter returns the rate limiter of the controllers' work queues. Failed reconciles are
+// retried with an exponential backoff from BaseDelay to MaxDelay. The backoff is jittered, so
+// claims failing together, e.g. during a store outage, spread their retries.
+func NewRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		&jitterRateLimiter{RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(BaseDelay, MaxDelay)},
+		// The overall limit of client-go's default controller rate limiter
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// jitterRateLimiter adds up to JitterFactor of the delay of its RateLimiter
+type jitterRateLimiter struct {
+	workqueue.RateLimiter
+}
+
+func (r *jitterRateLimiter) When(item interface{}) time.Duration {
+	return wait.Jitter(r.RateLimiter.When(item), JitterFactor)
+}
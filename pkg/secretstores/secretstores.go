@@ -0,0 +1,53 @@
+package secretstores
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Get resolves a SecretStoreRef from a claim in the given namespace. A ClusterSecretStore
+// is only returned if its namespaceSelector matches the claim's namespace.
+func Get(ctx context.Context, c client.Reader, ref v1alpha1.SecretStoreRef, namespace string) (v1alpha1.GenericStore, error) {
+	if ref.Kind != v1alpha1.ClusterSecretStoreKind {
+		var store v1alpha1.SecretStore
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &store); err != nil {
+			return nil, fmt.Errorf("unable to get secret store %s/%s: %w", namespace, ref.Name, err)
+		}
+		return &store, nil
+	}
+
+	var store v1alpha1.ClusterSecretStore
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, &store); err != nil {
+		return nil, fmt.Errorf("unable to get cluster secret store %s: %w", ref.Name, err)
+	}
+	allowed, err := namespaceAllowed(ctx, c, store.Spec.NamespaceSelector, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("namespace %s is not allowed to use cluster secret store %s", namespace, ref.Name)
+	}
+	return &store, nil
+}
+
+func namespaceAllowed(ctx context.Context, c client.Reader, namespaceSelector *metav1.LabelSelector, namespace string) (bool, error) {
+	if namespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, fmt.Errorf("unable to get namespace %s: %w", namespace, err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/secrets-operator/secrets-operator/pkg/controllerutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// New returns a ClusterIP service exposing the named container port of the selected pods.
+func New(name, namespace string, selector map[string]string, port int32, targetPort string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    selector,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       targetPort,
+				Port:       port,
+				TargetPort: intstr.FromString(targetPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// Reconcile creates the service or updates the existing one to match it.
+func Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, service corev1.Service, owner client.Object) error {
+	if err := controllerutil.SetControllerReference(owner, &service, scheme); err != nil {
+		return err
+	}
+
+	// First check if service exists
+	var existing corev1.Service
+	err := c.Get(ctx, types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, &existing)
+	if err != nil && apierrors.IsNotFound(err) {
+		return c.Create(ctx, &service)
+	} else if err != nil {
+		return fmt.Errorf("failed to get service %s/%s: %w", service.Namespace, service.Name, err)
+	}
+
+	// The cluster IP is allocated by the API server and cannot be changed.
+	service.Spec.ClusterIP = existing.Spec.ClusterIP
+	service.Spec.ClusterIPs = existing.Spec.ClusterIPs
+	service.ResourceVersion = existing.ResourceVersion
+	return c.Update(ctx, &service)
+}
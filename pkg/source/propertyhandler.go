@@ -1,15 +1,70 @@
 package source
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
 	"github.com/secrets-operator/secrets-operator/pkg/generation"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/retry"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func HandleProperty(propertySource v1alpha1.PropertySource) (string, error) {
+// HandleProperty sources a single value. Remote properties are read through store, which
+// is nil for claims without a secret store.
+func HandleProperty(ctx context.Context, propertySource v1alpha1.PropertySource, store providers.Client) (value string, err error) {
+	ctx, span := tracing.Start(ctx, "source.HandleProperty")
+	defer func() { tracing.End(span, err) }()
 	if propertySource.PropertyGenerator != nil {
-		return generation.Generate(*propertySource.PropertyGenerator)
+		span.SetAttributes(attribute.String("source", "generator"))
+		return generation.Generate(ctx, *propertySource.PropertyGenerator)
 	}
-	return "", fmt.Errorf("unable to determine how to source propery")
+	if remote := propertySource.Remote; remote != nil {
+		span.SetAttributes(attribute.String("source", "remote"), attribute.String("remote.key", remote.Key))
+		if store == nil {
+			return "", retry.Terminal(fmt.Errorf("remote property %s requires a secret store", remote.Key))
+		}
+		remoteValue, err := store.GetSecret(ctx, remote.Key, remote.Version)
+		if err != nil {
+			return "", providers.ProviderError(err)
+		}
+		return string(remoteValue), nil
+	}
+	return "", retry.Terminal(fmt.Errorf("unable to determine how to source propery"))
+}
+
+// HandleProperties sources the value of every property. Generated values already present in
+// existing are kept rather than generated again, remote values are always read from the store.
+func HandleProperties(ctx context.Context, properties []v1alpha1.SecretClaimProperty, existing map[string][]byte,
+	store providers.Client) (map[string][]byte, error) {
+	values := map[string][]byte{}
+	for _, property := range properties {
+		if value, ok := existing[property.Name]; ok && property.PropertySource.Remote == nil {
+			values[property.Name] = value
+			continue
+		}
+		sourcedProperty, err := HandleProperty(ctx, property.PropertySource, store)
+		if err != nil {
+			return nil, fmt.Errorf("error sourcing property %s: %w", property.Name, err)
+		}
+		values[property.Name] = []byte(sourcedProperty)
+	}
+	return values, nil
+}
+
+// GeneratedProperties returns the properties HandleProperties generates a new value for
+func GeneratedProperties(properties []v1alpha1.SecretClaimProperty, existing map[string][]byte) []v1alpha1.SecretClaimProperty {
+	var generated []v1alpha1.SecretClaimProperty
+	for _, property := range properties {
+		if property.PropertySource.PropertyGenerator == nil {
+			continue
+		}
+		if _, ok := existing[property.Name]; ok {
+			continue
+		}
+		generated = append(generated, property)
+	}
+	return generated
 }
@@ -0,0 +1,67 @@
+package storeagent
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+)
+
+// The store agent serves a versioned HTTPS API on Port, accepting only clients that present
+// the operator's client certificate:
+//
+//	GET    /v1/secrets                        lists the secret names
+//	GET    /v1/secrets/{name}?version=VERSION returns the secret, the latest version by default
+//	PUT    /v1/secrets/{name}                 writes a new version of the secret
+//	DELETE /v1/secrets/{name}                 deletes the secret
+//
+// Request and response bodies are JSON. Errors are returned as an ErrorResponse.
+const (
+	APIVersion = "v1"
+	Port       = 8443
+	// HealthPort serves the unauthenticated /healthz endpoint used by the readiness probe
+	HealthPort = 8081
+	// TLSDir is where the serving certificate Secret is mounted
+	TLSDir = "/etc/store-agent/tls"
+	// CredentialsDir is where the credentials Secret is mounted, one file per secretRef
+	CredentialsDir = "/etc/store-agent/credentials"
+	// ProviderEnv holds the JSON encoded v1alpha1.Provider the agent serves
+	ProviderEnv = "STORE_PROVIDER"
+)
+
+// SecretValue is the body of a secret read or write
+type SecretValue struct {
+	Value []byte `json:"value"`
+}
+
+// SecretList is the body of a list response
+type SecretList struct {
+	Names []string `json:"names"`
+}
+
+// ErrorResponse is the body of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// URL returns the base URL of the agent for the store deployment name in namespace
+func URL(name, namespace string) string {
+	return fmt.Sprintf("https://%s.%s.svc:%d", name, namespace, Port)
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// CredentialEnv returns the name of the environment variable the agent reads the value
+// of a secretRef credential from
+func CredentialEnv(ref v1alpha1.SecretRef) string {
+	name := strings.ToUpper(strings.Join([]string{ref.Namespace, ref.Name, ref.Key}, "_"))
+	return "CREDENTIAL_" + nonEnvChars.ReplaceAllString(name, "_")
+}
+
+// CredentialFile returns the file the agent reads the value of a secretRef credential from,
+// for credentials that a backend library expects in a file
+func CredentialFile(ref v1alpha1.SecretRef) string {
+	return path.Join(CredentialsDir, CredentialEnv(ref))
+}
@@ -0,0 +1,93 @@
+package storeagent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+)
+
+// Client is a providers.Client talking to a store agent
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+var _ providers.Client = &Client{}
+
+// NewClient returns a client for the agent at baseURL, see URL
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient}
+}
+
+func (c *Client) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	u := c.secretURL(name)
+	if version != "" {
+		u += "?version=" + url.QueryEscape(version)
+	}
+	var body SecretValue
+	if err := c.do(ctx, http.MethodGet, u, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Value, nil
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, value []byte) error {
+	return c.do(ctx, http.MethodPut, c.secretURL(name), SecretValue{Value: value}, nil)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, c.secretURL(name), nil, nil)
+}
+
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	var body SecretList
+	if err := c.do(ctx, http.MethodGet, c.baseURL+secretsPath, nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Names, nil
+}
+
+func (c *Client) secretURL(name string) string {
+	return c.baseURL + secretsPath + "/" + url.PathEscape(name)
+}
+
+func (c *Client) do(ctx context.Context, method, u string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(req)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("store agent request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var agentErr ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&agentErr)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("store agent: %s: %w", agentErr.Error, providers.ErrNotFound)
+		}
+		return providers.ThrottledResponse(resp,
+			fmt.Errorf("store agent %s %s returned %d: %s", method, req.URL.Path, resp.StatusCode, agentErr.Error))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
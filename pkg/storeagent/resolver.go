@@ -0,0 +1,27 @@
+package storeagent
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+)
+
+// EnvResolver resolves inline values, and secretRef values from the environment
+// variable named by CredentialEnv
+func EnvResolver(ctx context.Context, v v1alpha1.ValueOrSecretKey) (string, error) {
+	if v.Value != nil {
+		return *v.Value, nil
+	}
+	if v.SecretRef == nil {
+		return "", fmt.Errorf("neither value nor secretRef is set")
+	}
+	env := CredentialEnv(*v.SecretRef)
+	value, ok := os.LookupEnv(env)
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s key %s was not provided to the agent as %s",
+			v.SecretRef.Namespace, v.SecretRef.Name, v.SecretRef.Key, env)
+	}
+	return value, nil
+}
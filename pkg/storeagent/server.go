@@ -0,0 +1,119 @@
+package storeagent
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/tracing"
+)
+
+const secretsPath = "/" + APIVersion + "/secrets"
+
+// NewServer returns the handler of the agent API, serving secrets from client
+func NewServer(client providers.Client, log logr.Logger) http.Handler {
+	s := &server{client: client, log: log}
+	mux := http.NewServeMux()
+	mux.HandleFunc(secretsPath, s.list)
+	mux.HandleFunc(secretsPath+"/", s.secret)
+	return tracing.Handler(mux)
+}
+
+// NewHealthServer returns the handler of the health endpoint
+func NewHealthServer() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+type server struct {
+	client providers.Client
+	log    logr.Logger
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	names, err := s.client.ListSecrets(r.Context())
+	if err != nil {
+		s.writeProviderError(w, "list", "", err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, SecretList{Names: names})
+}
+
+func (s *server) secret(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, secretsPath+"/")
+	if name == "" || strings.Contains(name, "/") {
+		s.writeError(w, http.StatusBadRequest, errors.New("invalid secret name"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		value, err := s.client.GetSecret(r.Context(), name, r.URL.Query().Get("version"))
+		if err != nil {
+			s.writeProviderError(w, "get", name, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, SecretValue{Value: value})
+	case http.MethodPut:
+		var body SecretValue
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.client.PutSecret(r.Context(), name, body.Value); err != nil {
+			s.writeProviderError(w, "put", name, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := s.client.DeleteSecret(r.Context(), name); err != nil {
+			s.writeProviderError(w, "delete", name, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func (s *server) writeProviderError(w http.ResponseWriter, operation, name string, err error) {
+	if errors.Is(err, providers.ErrNotFound) {
+		s.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	s.log.Error(err, "provider call failed", "operation", operation, "secret", name)
+	var throttled *providers.ThrottledError
+	if errors.As(err, &throttled) {
+		// The agent passes the store's rate limit on, so the operator backs off as asked.
+		if throttled.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		}
+		s.writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	s.writeError(w, http.StatusBadGateway, err)
+}
+
+func (s *server) writeError(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.log.Error(err, "unable to write response")
+	}
+}
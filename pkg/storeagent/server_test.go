@@ -0,0 +1,121 @@
+package storeagent
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/secrets-operator/secrets-operator/pkg/certificates"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"github.com/secrets-operator/secrets-operator/pkg/providers/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// startAgent serves store over TLS with the serving Secret issued by authority for the
+// deployment agent in namespace tenant, as the store agent does
+func startAgent(t *testing.T, authority *certificates.Authority, store providers.Client) *httptest.Server {
+	t.Helper()
+	ctx := context.Background()
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "tenant", UID: "store-uid"}}
+	c := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(owner).Build()
+	if _, err := certificates.ReconcileServingSecret(ctx, c, clientgoscheme.Scheme, authority, "agent", "tenant", owner); err != nil {
+		t.Fatal(err)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant", Name: certificates.ServingSecretName("agent")}, &secret); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for name, data := range secret.Data {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reloader, err := certificates.NewFileReloader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(NewServer(store, logr.Discard()))
+	server.TLS = reloader.ServerTLSConfig()
+	// Rejected handshakes are expected
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newAgentClient returns a client of the agent using the manager's HTTP client of authority.
+// Connections to the agent's service are dialed to server.
+func newAgentClient(t *testing.T, authority *certificates.Authority, server *httptest.Server) *Client {
+	t.Helper()
+	httpClient, err := authority.HTTPClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := httpClient.Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	httpClient.Transport = transport
+	return NewClient(URL("agent", "tenant"), httpClient)
+}
+
+func newAuthority() *certificates.Authority {
+	c := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	return certificates.NewAuthority(c, c, "operator")
+}
+
+func TestClientCallsTheAgentWithTheManagerCertificate(t *testing.T) {
+	ctx := context.Background()
+	authority := newAuthority()
+	store := fake.NewClient(map[string][]byte{"db-password": []byte("s3cr3t")})
+	client := newAgentClient(t, authority, startAgent(t, authority, store))
+
+	value, err := client.GetSecret(ctx, "db-password", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "s3cr3t" {
+		t.Errorf("got %q, want s3cr3t", value)
+	}
+	if err := client.PutSecret(ctx, "api-token", []byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Secrets()["api-token"]; string(got) != "abc" {
+		t.Errorf("the store holds %q, want abc", got)
+	}
+	if _, err := client.GetSecret(ctx, "missing", ""); !errors.Is(err, providers.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, providers.ErrNotFound)
+	}
+}
+
+func TestAgentRejectsManagersOfOtherAuthorities(t *testing.T) {
+	authority := newAuthority()
+	server := startAgent(t, authority, fake.NewClient(nil))
+
+	// The other manager trusts the agent, but presents a certificate the agent does not trust
+	other := newAuthority()
+	otherClient := newAgentClient(t, other, server)
+	transport := otherClient.http.Transport.(*http.Transport)
+	bundle, err := authority.Bundle(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.TLSClientConfig.RootCAs.AppendCertsFromPEM(bundle)
+
+	if _, err := otherClient.ListSecrets(context.Background()); err == nil {
+		t.Error("the agent accepted a client certificate of another CA")
+	}
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// tracedKubeClient records a span for every API server request of a client.Client
+type tracedKubeClient struct {
+	client.Client
+}
+
+// WrapClient returns a client recording its requests to the API server as spans
+func WrapClient(c client.Client) client.Client {
+	return &tracedKubeClient{Client: c}
+}
+
+func objectAttributes(c client.Client, obj runtime.Object) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{}
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		attributes = append(attributes, attribute.String("k8s.kind", gvk.Kind))
+	}
+	if o, ok := obj.(client.Object); ok && o.GetName() != "" {
+		attributes = append(attributes,
+			attribute.String("k8s.namespace", o.GetNamespace()),
+			attribute.String("k8s.name", o.GetName()))
+	}
+	return attributes
+}
+
+func (c *tracedKubeClient) Get(ctx context.Context, key types.NamespacedName, obj client.Object) error {
+	ctx, span := Start(ctx, "k8s.Get", append(objectAttributes(c.Client, obj),
+		attribute.String("k8s.namespace", key.Namespace), attribute.String("k8s.name", key.Name))...)
+	err := c.Client.Get(ctx, key, obj)
+	End(span, client.IgnoreNotFound(err))
+	return err
+}
+
+func (c *tracedKubeClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, span := Start(ctx, "k8s.List", objectAttributes(c.Client, list)...)
+	err := c.Client.List(ctx, list, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedKubeClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := Start(ctx, "k8s.Create", objectAttributes(c.Client, obj)...)
+	err := c.Client.Create(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedKubeClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := Start(ctx, "k8s.Delete", objectAttributes(c.Client, obj)...)
+	err := c.Client.Delete(ctx, obj, opts...)
+	End(span, client.IgnoreNotFound(err))
+	return err
+}
+
+func (c *tracedKubeClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := Start(ctx, "k8s.Update", objectAttributes(c.Client, obj)...)
+	err := c.Client.Update(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedKubeClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := Start(ctx, "k8s.Patch", objectAttributes(c.Client, obj)...)
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedKubeClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, span := Start(ctx, "k8s.DeleteAllOf", objectAttributes(c.Client, obj)...)
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedKubeClient) Status() client.StatusWriter {
+	return &tracedStatusWriter{writer: c.Client.Status(), client: c.Client}
+}
+
+type tracedStatusWriter struct {
+	writer client.StatusWriter
+	client client.Client
+}
+
+func (w *tracedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := Start(ctx, "k8s.UpdateStatus", objectAttributes(w.client, obj)...)
+	err := w.writer.Update(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (w *tracedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := Start(ctx, "k8s.PatchStatus", objectAttributes(w.client, obj)...)
+	err := w.writer.Patch(ctx, obj, patch, opts...)
+	End(span, err)
+	return err
+}
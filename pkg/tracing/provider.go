@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/secrets-operator/secrets-operator/api/v1alpha1"
+	"github.com/secrets-operator/secrets-operator/pkg/providers"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedClient records a span for every call of a providers.Client
+type tracedClient struct {
+	client     providers.Client
+	attributes []attribute.KeyValue
+}
+
+var _ providers.Client = &tracedClient{}
+
+// InstrumentClient returns a client recording the calls to the store's client as spans
+func InstrumentClient(client providers.Client, store v1alpha1.GenericStore) providers.Client {
+	kind := v1alpha1.SecretStoreKind
+	if _, ok := store.(*v1alpha1.ClusterSecretStore); ok {
+		kind = v1alpha1.ClusterSecretStoreKind
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("store.kind", kind),
+		attribute.String("store.namespace", store.GetNamespace()),
+		attribute.String("store.name", store.GetName()),
+	}
+	if names := v1alpha1.ConfiguredProviders(&store.GetSpec().Provider); len(names) == 1 {
+		attributes = append(attributes, attribute.String("store.provider", names[0]))
+	}
+	return &tracedClient{client: client, attributes: attributes}
+}
+
+func (c *tracedClient) GetSecret(ctx context.Context, name, version string) ([]byte, error) {
+	ctx, span := Start(ctx, "provider.GetSecret", append(c.attributes, attribute.String("secret.name", name))...)
+	value, err := c.client.GetSecret(ctx, name, version)
+	End(span, err)
+	return value, err
+}
+
+func (c *tracedClient) PutSecret(ctx context.Context, name string, value []byte) error {
+	ctx, span := Start(ctx, "provider.PutSecret", append(c.attributes, attribute.String("secret.name", name))...)
+	err := c.client.PutSecret(ctx, name, value)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) DeleteSecret(ctx context.Context, name string) error {
+	ctx, span := Start(ctx, "provider.DeleteSecret", append(c.attributes, attribute.String("secret.name", name))...)
+	err := c.client.DeleteSecret(ctx, name)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) ListSecrets(ctx context.Context) ([]string, error) {
+	ctx, span := Start(ctx, "provider.ListSecrets", c.attributes...)
+	names, err := c.client.ListSecrets(ctx)
+	End(span, err)
+	return names, err
+}
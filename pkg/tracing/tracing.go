@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"flag"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/secrets-operator/secrets-operator"
+
+// Options configure the export of spans
+type Options struct {
+	// Endpoint is the host:port of an OTLP gRPC collector. Tracing is disabled if it is empty.
+	Endpoint string
+	// Insecure connects to the collector without TLS
+	Insecure bool
+	// SampleRatio is the fraction of traces recorded, unless the parent span was sampled
+	SampleRatio float64
+	// ServiceName identifies the process in traces
+	ServiceName string
+}
+
+// BindFlags registers the flags configuring the export on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "otlp-endpoint", "",
+		"The host:port of an OTLP gRPC collector spans are exported to. Tracing is disabled if empty.")
+	fs.BoolVar(&o.Insecure, "otlp-insecure", false, "Connect to the OTLP collector without TLS.")
+	fs.Float64Var(&o.SampleRatio, "trace-sample-ratio", 1, "The fraction of traces recorded.")
+}
+
+// Setup installs the global tracer provider exporting spans over OTLP. The returned function
+// flushes and stops the export.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", opts.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the operator's tracer
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Logger adds the trace and span IDs of the span in ctx to log, so log lines can be found from traces
+func Logger(ctx context.Context, log logr.Logger) logr.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return log
+	}
+	return log.WithValues("traceID", spanContext.TraceID().String(), "spanID", spanContext.SpanID().String())
+}